@@ -0,0 +1,291 @@
+// Package baseline checks tables against a known-good "golden" table for the
+// same hardware model, such as in CI gating for new deliveries.
+//
+// Structures are matched and compared field by field with the diff package.
+// Each differing field is then judged by the first matching rule: it must
+// match exactly (the default), fall within a numeric range, or is ignored.
+// DefaultRules ignores per-unit identity such as serial numbers and UUIDs.
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/diff"
+)
+
+// Mode is how a rule judges a field
+type Mode string
+
+// Rule modes
+const (
+	Exact  Mode = "exact"  // Value must equal the baseline
+	Range  Mode = "range"  // Numeric value must be within Min/Max or Tolerance
+	Ignore Mode = "ignore" // Value is not checked
+)
+
+// Rule judges the fields it matches
+type Rule struct {
+	// Types limits the rule to these structure types (empty for all)
+	Types []uint8 `json:"types,omitempty"`
+	// Field is a field path as reported by the diff package, such as
+	// "SerialNumber" or "ContainedElements[0].Maximum". '*' matches any
+	// sequence of characters.
+	Field string `json:"field"`
+	Mode  Mode   `json:"mode"`
+	// Min and Max bound the value for Range rules
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Tolerance allows this absolute difference from the baseline value for
+	// Range rules
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+// Rules is a rule set. Rules are tried in order and the first match wins;
+// DefaultRules are tried after them unless NoDefaults is set.
+type Rules struct {
+	Rules       []Rule  `json:"rules"`
+	IgnoreTypes []uint8 `json:"ignore_types,omitempty"`
+	NoDefaults  bool    `json:"no_defaults,omitempty"`
+}
+
+// DefaultRules ignores values that differ between units of the same model,
+// or between boots of the same unit
+var DefaultRules = []Rule{
+	{Field: "SerialNumber", Mode: Ignore},
+	{Field: "AssetTag", Mode: Ignore},
+	{Field: "AssetTagNumber", Mode: Ignore},
+	{Types: []uint8{1}, Field: "UUID", Mode: Ignore},
+	{Types: []uint8{1}, Field: "WakeUpType", Mode: Ignore},
+	{Types: []uint8{15}, Field: "LogChangeToken", Mode: Ignore},
+	{Types: []uint8{43}, Field: "OEMDefined", Mode: Ignore},
+}
+
+// ErrInvalidRule is returned for rules with an unknown mode or bad range
+var ErrInvalidRule = errors.New("smbios: invalid baseline rule")
+
+// ParseRules reads a JSON rule set
+func ParseRules(data []byte) (*Rules, error) {
+	var r Rules
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	for i, rule := range r.Rules {
+		switch rule.Mode {
+		case Exact, Ignore:
+		case Range:
+			if rule.Min == nil && rule.Max == nil && rule.Tolerance == 0 {
+				return nil, fmt.Errorf("%w: rule %d (%s) has no min, max or tolerance", ErrInvalidRule, i, rule.Field)
+			}
+		default:
+			return nil, fmt.Errorf("%w: rule %d (%s) has unknown mode %q", ErrInvalidRule, i, rule.Field, rule.Mode)
+		}
+		if rule.Field == "" {
+			return nil, fmt.Errorf("%w: rule %d has no field", ErrInvalidRule, i)
+		}
+	}
+	return &r, nil
+}
+
+// LoadRules reads a JSON rule set from a file
+func LoadRules(filename string) (*Rules, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(data)
+}
+
+// matches reports whether the rule applies to a field
+func (r *Rule) matches(structType uint8, field string) bool {
+	if len(r.Types) > 0 {
+		found := false
+		for _, t := range r.Types {
+			if t == structType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !strings.Contains(r.Field, "*") {
+		return r.Field == field
+	}
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(r.Field), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(pattern, field)
+	return ok
+}
+
+// rule returns the first rule matching a field, or nil for the default
+// exact match
+func (rs *Rules) rule(structType uint8, field string) *Rule {
+	for i := range rs.Rules {
+		if rs.Rules[i].matches(structType, field) {
+			return &rs.Rules[i]
+		}
+	}
+	if !rs.NoDefaults {
+		for i := range DefaultRules {
+			if DefaultRules[i].matches(structType, field) {
+				return &DefaultRules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Kind is the kind of deviation
+type Kind string
+
+// Deviation kinds
+const (
+	Missing    Kind = "missing"      // Structure in the baseline is absent
+	Unexpected Kind = "unexpected"   // Structure is not in the baseline
+	Mismatch   Kind = "mismatch"     // Field differs from the baseline
+	OutOfRange Kind = "out-of-range" // Field is outside the rule's range
+)
+
+// Deviation is a difference from the baseline that the rules do not allow
+type Deviation struct {
+	Kind     Kind   `json:"kind"`
+	Type     uint8  `json:"type"`
+	TypeName string `json:"type_name"`
+	Key      string `json:"key,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// String returns a human-readable description of the deviation
+func (d Deviation) String() string {
+	where := fmt.Sprintf("Type %d (%s)", d.Type, d.TypeName)
+	if d.Key != "" {
+		where += " [" + d.Key + "]"
+	}
+	switch d.Kind {
+	case Missing:
+		return where + ": missing"
+	case Unexpected:
+		return where + ": not in baseline"
+	case OutOfRange:
+		return fmt.Sprintf("%s: %s = %q out of range (%s)", where, d.Field, d.Actual, d.Detail)
+	default:
+		return fmt.Sprintf("%s: %s = %q, expected %q", where, d.Field, d.Actual, d.Expected)
+	}
+}
+
+// Result is the outcome of checking one table
+type Result struct {
+	Source     string      `json:"source,omitempty"`
+	Deviations []Deviation `json:"deviations"`
+}
+
+// Passed returns true if the table conforms to the baseline
+func (r *Result) Passed() bool {
+	return len(r.Deviations) == 0
+}
+
+// Check compares a table against the baseline. A nil rule set uses
+// DefaultRules only.
+func Check(golden, sm *gosmbios.SMBIOS, rules *Rules) *Result {
+	if rules == nil {
+		rules = &Rules{}
+	}
+	d := diff.CompareWith(golden, sm, diff.Options{IgnoreTypes: rules.IgnoreTypes})
+
+	r := &Result{Deviations: []Deviation{}}
+	for _, c := range d.Changes {
+		dev := Deviation{Type: c.Type, TypeName: c.TypeName, Key: c.Key}
+		switch c.Kind {
+		case diff.Removed:
+			dev.Kind = Missing
+			r.Deviations = append(r.Deviations, dev)
+			continue
+		case diff.Added:
+			dev.Kind = Unexpected
+			r.Deviations = append(r.Deviations, dev)
+			continue
+		}
+
+		for _, f := range c.Fields {
+			rule := rules.rule(c.Type, f.Field)
+			dev := dev
+			dev.Field = f.Field
+			dev.Expected = f.Before
+			dev.Actual = f.After
+
+			switch {
+			case rule == nil || rule.Mode == Exact:
+				dev.Kind = Mismatch
+			case rule.Mode == Ignore:
+				continue
+			default:
+				detail, ok := inRange(rule, f.Before, f.After)
+				if ok {
+					continue
+				}
+				dev.Kind = OutOfRange
+				dev.Detail = detail
+			}
+			r.Deviations = append(r.Deviations, dev)
+		}
+	}
+	return r
+}
+
+// inRange checks a value against a Range rule, returning a description of
+// the allowed range
+func inRange(rule *Rule, expected, actual string) (string, bool) {
+	v, err := strconv.ParseFloat(actual, 64)
+	if err != nil {
+		return "not numeric", false
+	}
+
+	var limits []string
+	ok := true
+	if rule.Min != nil {
+		limits = append(limits, fmt.Sprintf("min %g", *rule.Min))
+		ok = ok && v >= *rule.Min
+	}
+	if rule.Max != nil {
+		limits = append(limits, fmt.Sprintf("max %g", *rule.Max))
+		ok = ok && v <= *rule.Max
+	}
+	if rule.Tolerance != 0 {
+		base, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return "baseline not numeric", false
+		}
+		limits = append(limits, fmt.Sprintf("%g ± %g", base, rule.Tolerance))
+		ok = ok && v >= base-rule.Tolerance && v <= base+rule.Tolerance
+	}
+	return strings.Join(limits, ", "), ok
+}
+
+// WriteText writes the deviations, one per line, followed by PASS or FAIL
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if r.Source != "" {
+		fmt.Fprintf(&b, "%s:\n", r.Source)
+	}
+	for _, d := range r.Deviations {
+		fmt.Fprintf(&b, "  %s\n", d)
+	}
+	if r.Passed() {
+		b.WriteString("  PASS\n")
+	} else {
+		fmt.Fprintf(&b, "  FAIL (%d deviations)\n", len(r.Deviations))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package gosmbios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrStructureTooLong is returned when a formatted section does not fit the
+// one-byte Length field of the structure header
+var ErrStructureTooLong = errors.New("smbios: formatted section longer than 251 bytes")
+
+// NewStructure builds a structure from its formatted section (without the
+// 4-byte header) and string table. The header length is derived from the
+// formatted section. String references in the formatted section are 1-based
+// indexes into strs, as in a table read from firmware. The header and
+// formatted section together may not exceed 255 bytes.
+func NewStructure(structType uint8, handle uint16, formatted []byte, strs []string) (Structure, error) {
+	if 4+len(formatted) > 255 {
+		return Structure{}, ErrStructureTooLong
+	}
+	data := make([]byte, 4+len(formatted))
+	data[0] = structType
+	data[1] = uint8(len(data))
+	binary.LittleEndian.PutUint16(data[2:], handle)
+	copy(data[4:], formatted)
+
+	s := Structure{
+		Header: Header{
+			Type:   structType,
+			Length: uint8(len(data)),
+			Handle: handle,
+		},
+		Data: data,
+	}
+	if len(strs) > 0 {
+		s.Strings = append([]string(nil), strs...)
+	}
+	return s, nil
+}
+
+// Bytes returns the structure as it appears in the SMBIOS table:
+// the formatted section followed by the double-null terminated string table.
+// RawStrings are used when present, otherwise Strings.
+func (s *Structure) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(s.Data)
+
+	switch {
+	case len(s.RawStrings) > 0:
+		for _, raw := range s.RawStrings {
+			buf.Write(raw)
+			buf.WriteByte(0)
+		}
+		buf.WriteByte(0)
+	case s.RawStrings == nil && len(s.Strings) > 0:
+		for _, str := range s.Strings {
+			buf.WriteString(str)
+			buf.WriteByte(0)
+		}
+		buf.WriteByte(0)
+	default:
+		// Empty string table: two null bytes
+		buf.WriteByte(0)
+		buf.WriteByte(0)
+	}
+
+	return buf.Bytes()
+}
+
+// TableBytes returns the raw SMBIOS structure table, as it would be found in
+// firmware memory, by concatenating the Bytes of every structure
+func (sm *SMBIOS) TableBytes() []byte {
+	var buf bytes.Buffer
+	for i := range sm.Structures {
+		buf.Write(sm.Structures[i].Bytes())
+	}
+	return buf.Bytes()
+}
+
+// StringTable collects the strings of a structure being built and assigns
+// their 1-based indexes
+type StringTable struct {
+	strs []string
+}
+
+// Add appends s and returns its string number. Empty strings return 0 (no
+// string), repeated strings share one number, and 0 is also returned once the
+// table holds the maximum of 255 strings.
+func (t *StringTable) Add(s string) uint8 {
+	if s == "" {
+		return 0
+	}
+	for i, existing := range t.strs {
+		if existing == s {
+			return uint8(i + 1)
+		}
+	}
+	if len(t.strs) >= 255 {
+		return 0
+	}
+	t.strs = append(t.strs, s)
+	return uint8(len(t.strs))
+}
+
+// Strings returns the collected strings in string number order
+func (t *StringTable) Strings() []string {
+	return t.strs
+}
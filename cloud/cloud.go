@@ -0,0 +1,341 @@
+// Package cloud identifies the cloud provider a system runs on and extracts
+// the instance identity that providers encode in SMBIOS, without contacting
+// a metadata endpoint.
+//
+// Providers are described by a match function and an extract function over
+// the identity fields of Types 0, 1, 2, 3 and 11. Additional providers can be
+// added with RegisterProvider.
+package cloud
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type11"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type3"
+)
+
+// Provider names
+const (
+	AWS          = "AWS"
+	GCE          = "GCE"
+	Azure        = "Azure"
+	Oracle       = "Oracle"
+	Alibaba      = "Alibaba"
+	Hetzner      = "Hetzner"
+	DigitalOcean = "DigitalOcean"
+	OpenStack    = "OpenStack"
+)
+
+// Fields holds the SMBIOS identity values providers are matched against
+type Fields struct {
+	BIOSVendor          string
+	BIOSVersion         string
+	SystemManufacturer  string
+	SystemProduct       string
+	SystemVersion       string
+	SystemSerial        string
+	SystemFamily        string
+	SystemUUID          string // Empty if not set
+	BoardManufacturer   string
+	ChassisManufacturer string
+	ChassisAssetTag     string
+	OEMStrings          []string
+}
+
+// Identity is the detected cloud identity
+type Identity struct {
+	Provider       string // Empty if no provider matched
+	InstanceID     string // Provider instance ID, where encoded
+	InstanceType   string // Full instance type, e.g. "m5.large"
+	InstanceFamily string // Instance family, e.g. "m5"
+	Region         string // Region hint, where encoded
+	Zone           string // Availability zone hint, where encoded
+	Evidence       []string
+}
+
+// IsCloud returns true if a cloud provider was identified
+func (id *Identity) IsCloud() bool {
+	return id.Provider != ""
+}
+
+// Provider describes how to recognise a cloud and read its identity
+type Provider struct {
+	Name string
+	// Match returns the evidence for this provider, or nil if it does not match
+	Match func(f *Fields) []string
+	// Extract fills in the instance details; it may be nil
+	Extract func(f *Fields, id *Identity)
+}
+
+var (
+	instanceIDAWS = regexp.MustCompile(`^i-[0-9a-f]{8,17}$`)
+	numericID     = regexp.MustCompile(`^[0-9]+$`)
+	awsType       = regexp.MustCompile(`^([a-z][a-z0-9-]*)\.([a-z0-9-]+)$`)
+	hetznerType   = regexp.MustCompile(`^(cx|cpx|cax|ccx)[0-9]{2,3}$`)
+)
+
+// azureAssetTag is the chassis asset tag Azure sets on every VM
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+var (
+	providersMu sync.RWMutex
+	providers   = []Provider{
+		{
+			Name: AWS,
+			Match: func(f *Fields) []string {
+				var ev []string
+				if eq(f.SystemManufacturer, "Amazon EC2") {
+					ev = append(ev, "system.manufacturer=Amazon EC2")
+				}
+				if eq(f.BIOSVendor, "Amazon EC2") {
+					ev = append(ev, "bios.vendor=Amazon EC2")
+				}
+				if strings.Contains(strings.ToLower(f.BIOSVersion), "amazon") {
+					ev = append(ev, "bios.version contains amazon (Xen-based EC2)")
+				}
+				// A random UUID can start with EC2, so this only supports other evidence
+				if len(ev) > 0 && (hasPrefixFold(f.SystemUUID, "ec2") || hasPrefixFold(f.SystemSerial, "ec2")) {
+					ev = append(ev, "system uuid/serial starts with ec2")
+				}
+				return ev
+			},
+			Extract: func(f *Fields, id *Identity) {
+				// Nitro instances carry the instance ID in the chassis asset tag
+				if instanceIDAWS.MatchString(f.ChassisAssetTag) {
+					id.InstanceID = f.ChassisAssetTag
+				}
+				// and the instance type in the system product name
+				if m := awsType.FindStringSubmatch(f.SystemProduct); m != nil {
+					id.InstanceType = f.SystemProduct
+					id.InstanceFamily = m[1]
+				}
+			},
+		},
+		{
+			Name: GCE,
+			Match: func(f *Fields) []string {
+				var ev []string
+				if eq(f.SystemProduct, "Google Compute Engine") {
+					ev = append(ev, "system.product=Google Compute Engine")
+				}
+				if hasPrefixFold(f.SystemSerial, "GoogleCloud-") {
+					ev = append(ev, "system.serial starts with GoogleCloud-")
+				}
+				// Chromebooks are also made by Google, so this only supports
+				// other evidence
+				if len(ev) > 0 && eq(f.SystemManufacturer, "Google") {
+					ev = append(ev, "system.manufacturer=Google")
+				}
+				return ev
+			},
+		},
+		{
+			Name: Azure,
+			Match: func(f *Fields) []string {
+				if f.ChassisAssetTag == azureAssetTag {
+					return []string{"chassis.asset_tag=" + azureAssetTag}
+				}
+				return nil
+			},
+			Extract: func(f *Fields, id *Identity) {
+				// The Type 1 UUID is the Azure VM ID
+				id.InstanceID = strings.ToLower(f.SystemUUID)
+			},
+		},
+		{
+			Name: Oracle,
+			Match: func(f *Fields) []string {
+				if eq(f.ChassisAssetTag, "OracleCloud.com") {
+					return []string{"chassis.asset_tag=OracleCloud.com"}
+				}
+				return nil
+			},
+		},
+		{
+			Name: Alibaba,
+			Match: func(f *Fields) []string {
+				var ev []string
+				if eq(f.SystemManufacturer, "Alibaba Cloud") {
+					ev = append(ev, "system.manufacturer=Alibaba Cloud")
+				}
+				if hasPrefixFold(f.SystemProduct, "Alibaba Cloud ECS") {
+					ev = append(ev, "system.product=Alibaba Cloud ECS")
+				}
+				return ev
+			},
+		},
+		{
+			Name: Hetzner,
+			Match: func(f *Fields) []string {
+				if eq(f.SystemManufacturer, "Hetzner") {
+					return []string{"system.manufacturer=Hetzner"}
+				}
+				return nil
+			},
+			Extract: func(f *Fields, id *Identity) {
+				// Cloud servers carry the numeric server ID as serial number
+				if numericID.MatchString(f.SystemSerial) {
+					id.InstanceID = f.SystemSerial
+				}
+				// The product is usually "vServer"; only a server type such
+				// as cx22 names the instance type
+				if m := hetznerType.FindStringSubmatch(strings.ToLower(f.SystemProduct)); m != nil {
+					id.InstanceType = strings.ToLower(f.SystemProduct)
+					id.InstanceFamily = m[1]
+				}
+			},
+		},
+		{
+			Name: DigitalOcean,
+			Match: func(f *Fields) []string {
+				var ev []string
+				if eq(f.SystemManufacturer, "DigitalOcean") {
+					ev = append(ev, "system.manufacturer=DigitalOcean")
+				}
+				if eq(f.SystemProduct, "Droplet") {
+					ev = append(ev, "system.product=Droplet")
+				}
+				return ev
+			},
+			Extract: func(f *Fields, id *Identity) {
+				// The droplet ID is the system serial number
+				if numericID.MatchString(f.SystemSerial) {
+					id.InstanceID = f.SystemSerial
+				}
+			},
+		},
+		{
+			Name: OpenStack,
+			Match: func(f *Fields) []string {
+				var ev []string
+				if hasPrefixFold(f.SystemProduct, "OpenStack") {
+					ev = append(ev, "system.product="+f.SystemProduct)
+				}
+				if hasPrefixFold(f.SystemManufacturer, "OpenStack") {
+					ev = append(ev, "system.manufacturer="+f.SystemManufacturer)
+				}
+				if len(ev) > 0 && eq(f.SystemFamily, "Virtual Machine") {
+					ev = append(ev, "system.family=Virtual Machine")
+				}
+				return ev
+			},
+			Extract: func(f *Fields, id *Identity) {
+				// Nova sets the Type 1 UUID to the instance UUID
+				id.InstanceID = strings.ToLower(f.SystemUUID)
+			},
+		},
+	}
+)
+
+// RegisterProvider adds a provider. Providers are tried in registration
+// order after the built-in ones; the first match wins.
+func RegisterProvider(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers = append(providers, p)
+}
+
+// eq compares case-insensitively after trimming
+func eq(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), b)
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// Collect reads the identity fields used for provider detection
+func Collect(sm *gosmbios.SMBIOS) *Fields {
+	f := &Fields{}
+	if bios, err := type0.Get(sm); err == nil {
+		f.BIOSVendor = bios.Vendor
+		f.BIOSVersion = bios.Version
+	}
+	if sys, err := type1.Get(sm); err == nil {
+		f.SystemManufacturer = sys.Manufacturer
+		f.SystemProduct = sys.ProductName
+		f.SystemVersion = sys.Version
+		f.SystemSerial = sys.SerialNumber
+		f.SystemFamily = sys.Family
+		if sys.UUID.IsMeaningful() {
+			f.SystemUUID = sys.UUID.String()
+		}
+	}
+	if board, err := type2.Get(sm); err == nil {
+		f.BoardManufacturer = board.Manufacturer
+	}
+	if chassis, err := type3.Get(sm); err == nil {
+		f.ChassisManufacturer = chassis.Manufacturer
+		f.ChassisAssetTag = strings.TrimSpace(chassis.AssetTag)
+	}
+	if oems, err := type11.GetAll(sm); err == nil {
+		for _, oem := range oems {
+			f.OEMStrings = append(f.OEMStrings, oem.Strings...)
+		}
+	}
+	return f
+}
+
+// Detect identifies the cloud provider and instance identity.
+// The returned Identity has an empty Provider if no provider matched.
+func Detect(sm *gosmbios.SMBIOS) *Identity {
+	return DetectFields(Collect(sm))
+}
+
+// DetectFields identifies the cloud provider from already collected fields
+func DetectFields(f *Fields) *Identity {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	id := &Identity{}
+	for _, p := range providers {
+		ev := p.Match(f)
+		if len(ev) == 0 {
+			continue
+		}
+		id.Provider = p.Name
+		id.Evidence = ev
+		if p.Extract != nil {
+			p.Extract(f, id)
+		}
+		break
+	}
+
+	if id.Provider != "" {
+		applyOEMHints(f, id)
+	}
+	return id
+}
+
+// applyOEMHints fills region and zone from Type 11 strings of the form
+// "region=..." or "availability-zone: ...", which some provisioning tools add
+func applyOEMHints(f *Fields, id *Identity) {
+	for _, s := range f.OEMStrings {
+		i := strings.IndexAny(s, "=:")
+		if i <= 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		value := strings.TrimSpace(s[i+1:])
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "region":
+			if id.Region == "" {
+				id.Region = value
+			}
+		case "zone", "availability-zone", "availability_zone":
+			if id.Zone == "" {
+				id.Zone = value
+			}
+		}
+	}
+}
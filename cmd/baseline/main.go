@@ -0,0 +1,130 @@
+// smbiosbaseline - Tool to check SMBIOS dumps against a golden baseline
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/baseline"
+)
+
+// liveSource reads the table from the running system
+const liveSource = "live"
+
+func main() {
+	baselineFile := flag.String("b", "", "Baseline (golden) dump file")
+	rulesFile := flag.String("r", "", "JSON rules file (default: built-in rules only)")
+	format := flag.String("f", "text", "Output format: text, json")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp || *baselineFile == "" {
+		printUsage()
+		if *showHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	golden, err := load(*baselineFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading baseline %s: %v\n", *baselineFile, err)
+		os.Exit(2)
+	}
+
+	var rules *baseline.Rules
+	if *rulesFile != "" {
+		rules, err = baseline.LoadRules(*rulesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading rules %s: %v\n", *rulesFile, err)
+			os.Exit(2)
+		}
+	}
+
+	sources := flag.Args()
+	if len(sources) == 0 {
+		sources = []string{liveSource}
+	}
+
+	var results []*baseline.Result
+	failed := false
+	errored := false
+	for _, source := range sources {
+		sm, err := load(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", source, err)
+			errored = true
+			continue
+		}
+		result := baseline.Check(golden, sm, rules)
+		result.Source = source
+		results = append(results, result)
+		if !result.Passed() {
+			failed = true
+		}
+	}
+
+	switch strings.ToLower(*format) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	default:
+		for _, result := range results {
+			if err = result.WriteText(os.Stdout); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Exit status: 0 all conform, 1 deviations found, 2 error
+	switch {
+	case errored:
+		os.Exit(2)
+	case failed:
+		os.Exit(1)
+	}
+}
+
+// load reads a table from a dump file, or from the system for "live"
+func load(source string) (*gosmbios.SMBIOS, error) {
+	if source == liveSource {
+		return gosmbios.Read()
+	}
+	return gosmbios.ReadFromFile(source)
+}
+
+func printUsage() {
+	fmt.Println("smbiosbaseline - Check SMBIOS dumps against a golden baseline")
+	fmt.Println()
+	fmt.Println("Usage: smbiosbaseline -b <baseline> [options] [<dump>...]")
+	fmt.Println()
+	fmt.Println("Each dump is a dump file or \"live\" for the running system (the default).")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -b <file>   Baseline (golden) dump file")
+	fmt.Println("  -r <file>   JSON rules file (default: built-in rules only)")
+	fmt.Println("  -f <format> Output format: text, json (default: text)")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Fields must match the baseline exactly unless a rule says otherwise.")
+	fmt.Println("Serial numbers, asset tags, the system UUID and the wake-up type are")
+	fmt.Println("ignored by default.")
+	fmt.Println()
+	fmt.Println("Rules file example:")
+	fmt.Println(`  {"rules": [`)
+	fmt.Println(`    {"types": [4], "field": "CurrentSpeed", "mode": "range", "tolerance": 100},`)
+	fmt.Println(`    {"types": [17], "field": "Manufacturer", "mode": "ignore"},`)
+	fmt.Println(`    {"field": "*Handle", "mode": "ignore"}`)
+	fmt.Println(`  ], "ignore_types": [15]}`)
+	fmt.Println()
+	fmt.Println("Exit status is 0 if all dumps conform, 1 on deviations, 2 on error.")
+}
@@ -177,6 +177,9 @@ func main() {
 	debugType45(sm)
 	debugType46(sm)
 
+	debugInactive(sm)
+	debugOEM(sm)
+
 	// Debug any remaining/unknown types with raw data
 	debugRemainingTypes(sm, typeCounts)
 }
@@ -363,6 +366,18 @@ func debugType4(sm *gosmbios.SMBIOS) {
 			fmt.Printf("  Version:         %q\n", proc.ProcessorVersion)
 			fmt.Printf("  Family:          %s (0x%04X)\n", proc.ProcessorFamily.String(), uint16(proc.ProcessorFamily))
 			fmt.Printf("  ID:              0x%016X\n", proc.ProcessorID)
+			if id, ok := proc.CPUID(); ok {
+				fmt.Printf("  CPUID:           %s (type %d, ext family 0x%02X, ext model 0x%X)\n", id.String(), id.Type, id.ExtendedFamily, id.ExtendedModel)
+				fmt.Printf("  Uarch:           %q\n", proc.Microarchitecture())
+				fmt.Printf("  Flags:           0x%08X %s\n", uint32(id.Features), id.Features.String())
+			}
+			if midr, ok := proc.MIDR(); ok {
+				fmt.Printf("  MIDR:            0x%08X implementer 0x%02X variant %d arch 0x%X part 0x%03X revision %d (%s)\n",
+					midr.Value, midr.Implementer, midr.Variant, midr.Architecture, midr.PartNumber, midr.Revision, midr.String())
+			}
+			if soc, ok := proc.SoCID(); ok {
+				fmt.Printf("  SoC ID:          version 0x%08X revision 0x%08X (%s)\n", soc.Version, soc.Revision, soc.String())
+			}
 			fmt.Printf("  Voltage:         %s\n", proc.Voltage.String())
 			fmt.Printf("  External Clock:  %d MHz\n", proc.ExternalClock)
 			fmt.Printf("  Max Speed:       %d MHz\n", proc.MaxSpeed)
@@ -763,6 +778,11 @@ func debugType17(sm *gosmbios.SMBIOS) {
 			fmt.Printf("  Serial Number:   %q\n", mem.SerialNumber)
 			fmt.Printf("  Asset Tag:       %q\n", mem.AssetTag)
 			fmt.Printf("  Part Number:     %q\n", mem.PartNumber)
+			fmt.Printf("  Vendor:          %s\n", mem.Vendor())
+			fmt.Printf("  Module Mfr ID:   0x%04X (%s)\n", mem.ModuleManufacturerID, mem.ModuleManufacturer())
+			fmt.Printf("  Controller ID:   0x%04X (%s)\n", mem.MemorySubsystemControllerManufacturerID, mem.MemorySubsystemControllerManufacturer())
+			fmt.Printf("  PMIC0 Mfr ID:    0x%04X (%s) rev 0x%04X\n", mem.PMIC0ManufacturerID, mem.PMIC0Manufacturer(), mem.PMIC0RevisionNumber)
+			fmt.Printf("  RCD Mfr ID:      0x%04X (%s) rev 0x%04X\n", mem.RCDManufacturerID, mem.RCDManufacturer(), mem.RCDRevisionNumber)
 			fmt.Printf("  Ranks:           %d\n", mem.Ranks())
 			fmt.Printf("  Voltage:         %s\n", mem.VoltageString())
 			fmt.Printf("  Is Populated:    %v\n", mem.IsPopulated())
@@ -1332,7 +1352,12 @@ func debugType38(sm *gosmbios.SMBIOS) {
 			fmt.Printf("  I2C Address:     %s\n", ipmi.I2CAddressString())
 			fmt.Printf("  NV Storage:      0x%02X\n", ipmi.NVStorageDeviceAddress)
 			fmt.Printf("  Base Address:    %s\n", ipmi.BaseAddressString())
-			fmt.Printf("  Base Addr Mod:   0x%02X\n", ipmi.BaseAddressModifier)
+			fmt.Printf("  Raw Base Addr:   0x%016X\n", ipmi.BaseAddress)
+			fmt.Printf("  Base Addr Mod:   0x%02X (%s, LS-bit %v)\n", uint8(ipmi.BaseAddressModifier), ipmi.BaseAddressModifier, ipmi.BaseAddressModifier.IsLSBit())
+			fmt.Printf("  Reg Spacing:     %d byte(s)\n", ipmi.RegisterSpacingBytes())
+			if params, err := ipmi.KernelParameters(); err == nil {
+				fmt.Printf("  Kernel Params:   %s\n", params)
+			}
 			fmt.Printf("  Interrupt:       %s\n", ipmi.InterruptNumberString())
 		}
 		printStrings(s.Strings, "  ")
@@ -1456,12 +1481,27 @@ func debugType42(sm *gosmbios.SMBIOS) {
 			if len(mchi.InterfaceTypeSpecificData) > 0 {
 				fmt.Printf("  IF Data:         %s\n", hex.EncodeToString(mchi.InterfaceTypeSpecificData))
 			}
+			if d := mchi.NetworkDevice; d != nil {
+				fmt.Printf("  Device Type:     %s (0x%02X)\n", d.Type.String(), uint8(d.Type))
+				fmt.Printf("  Device:          %s\n", d)
+				if d.Characteristics != 0 {
+					fmt.Printf("  Characteristics: 0x%04X (credential bootstrapping: %v)\n", uint16(d.Characteristics), d.Characteristics.Has(type42.CharCredentialBootstrapping))
+					fmt.Printf("  Credential Bootstrapping Handle: 0x%04X\n", d.CredentialBootstrappingHandle)
+				}
+			}
 			fmt.Printf("  Protocol Records:%d\n", len(mchi.ProtocolRecords))
 			for j, pr := range mchi.ProtocolRecords {
 				fmt.Printf("    Protocol %d: %s (0x%02X)\n", j, pr.ProtocolType.String(), uint8(pr.ProtocolType))
 				if len(pr.ProtocolTypeSpecific) > 0 {
 					fmt.Printf("      Data: %s\n", hex.EncodeToString(pr.ProtocolTypeSpecific))
 				}
+				if rf, err := pr.RedfishOverIP(); err == nil {
+					fmt.Printf("      Service UUID:   %s\n", rf.ServiceUUID)
+					fmt.Printf("      Host IP:        %s mask %s (%s, %s)\n", rf.HostIP, rf.HostMask, rf.HostIPAssignment, rf.HostIPFormat)
+					fmt.Printf("      Service IP:     %s mask %s (%s, %s)\n", rf.ServiceIP, rf.ServiceMask, rf.ServiceIPDiscovery, rf.ServiceIPFormat)
+					fmt.Printf("      Service Port:   %d, VLAN: %d\n", rf.ServicePort, rf.ServiceVLAN)
+					fmt.Printf("      Hostname:       %s\n", rf.ServiceHostname)
+				}
 			}
 		}
 		printStrings(s.Strings, "  ")
@@ -1519,6 +1559,21 @@ func debugType44(sm *gosmbios.SMBIOS) {
 			if len(pai.ProcessorSpecificBlock.Data) > 0 {
 				fmt.Printf("  Block Data:      %s\n", hex.EncodeToString(pai.ProcessorSpecificBlock.Data))
 			}
+			if rv, err := pai.ProcessorSpecificBlock.RISCV(); err == nil {
+				fmt.Printf("  Revision:        %s\n", rv.RevisionString())
+				fmt.Printf("  Hart ID:         %s (boot hart: %v)\n", rv.HartID, rv.BootHart)
+				fmt.Printf("  mvendorid:       %s\n", rv.MachineVendorID)
+				fmt.Printf("  marchid:         %s\n", rv.MachineArchID)
+				fmt.Printf("  mimpid:          %s\n", rv.MachineImplID)
+				fmt.Printf("  ISA:             %s (0x%08X)\n", rv.ISAString(), uint32(rv.InstructionSet))
+				fmt.Printf("  Privilege:       %s (0x%02X)\n", rv.PrivilegeLevels, uint8(rv.PrivilegeLevels))
+				fmt.Printf("  medeleg:         %s\n", rv.MachineExceptionDelegation)
+				fmt.Printf("  mideleg:         %s\n", rv.MachineInterruptDelegation)
+				fmt.Printf("  XLEN:            %s (M: %s, S: %s, U: %s)\n", rv.XLEN, rv.MachineXLEN, rv.SupervisorXLEN, rv.UserXLEN)
+			} else if la, err := pai.ProcessorSpecificBlock.LoongArch(); err == nil {
+				fmt.Printf("  Revision:        %s\n", la.RevisionString())
+				printHexDump(la.Data, "  ")
+			}
 		}
 		printStrings(s.Strings, "  ")
 	}
@@ -1581,6 +1636,41 @@ func debugType46(sm *gosmbios.SMBIOS) {
 	}
 }
 
+func debugInactive(sm *gosmbios.SMBIOS) {
+	structs := sm.InactiveStructures()
+	if len(structs) == 0 {
+		return
+	}
+
+	fmt.Println("\n--- Type 126: Inactive ---")
+	for i, s := range structs {
+		fmt.Printf("[%d]\n", i)
+		printStructureHeader(&s)
+		for _, t := range s.CandidateTypes() {
+			fmt.Printf("  Possibly:        Type %d - %s\n", t, types.TypeName(t))
+		}
+		printHexDump(s.Data, "  ")
+		printStrings(s.Strings, "  ")
+	}
+}
+
+func debugOEM(sm *gosmbios.SMBIOS) {
+	oems := sm.OEMStructures()
+	if len(oems) == 0 {
+		return
+	}
+
+	fmt.Println("\n--- OEM-specific Types ---")
+	for i, oem := range oems {
+		fmt.Printf("[%d]\n", i)
+		printStructureHeader(&oem.Structure)
+		fmt.Printf("  Vendor Hint:     %q\n", oem.Vendor)
+		fmt.Printf("  OEM Type Name:   %q\n", oem.TypeName)
+		printHexDump(oem.Data, "  ")
+		printStrings(oem.Strings, "  ")
+	}
+}
+
 func debugRemainingTypes(sm *gosmbios.SMBIOS, typeCounts map[uint8]int) {
 	// Types we've already handled
 	handled := map[uint8]bool{
@@ -1592,11 +1682,11 @@ func debugRemainingTypes(sm *gosmbios.SMBIOS, typeCounts map[uint8]int) {
 		27: true, 28: true, 29: true, 30: true, 31: true, 32: true,
 		33: true, 34: true, 35: true, 36: true, 37: true,
 		38: true, 39: true, 40: true, 41: true, 42: true, 43: true,
-		44: true, 45: true, 46: true, 127: true,
+		44: true, 45: true, 46: true, 126: true, 127: true,
 	}
 
 	for structType := range typeCounts {
-		if handled[structType] {
+		if handled[structType] || structType >= gosmbios.OEMTypeMin {
 			continue
 		}
 
@@ -0,0 +1,122 @@
+// smbiosdiff - Tool to compare two SMBIOS tables
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/diff"
+)
+
+// liveSource reads the table from the running system
+const liveSource = "live"
+
+func main() {
+	outputFile := flag.String("o", "", "Output file path (default: stdout)")
+	format := flag.String("f", "text", "Output format: text, json")
+	ignoreTypes := flag.String("ignore", "", "Comma-separated structure types to ignore (e.g. 15,18)")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp || flag.NArg() < 1 || flag.NArg() > 2 {
+		printUsage()
+		if *showHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	beforeSource := flag.Arg(0)
+	afterSource := liveSource
+	if flag.NArg() == 2 {
+		afterSource = flag.Arg(1)
+	}
+
+	before, err := load(beforeSource)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", beforeSource, err)
+		os.Exit(2)
+	}
+	after, err := load(afterSource)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", afterSource, err)
+		os.Exit(2)
+	}
+
+	var opts diff.Options
+	if *ignoreTypes != "" {
+		for _, t := range strings.Split(*ignoreTypes, ",") {
+			var n uint8
+			if _, err := fmt.Sscanf(strings.TrimSpace(t), "%d", &n); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid structure type: %q\n", t)
+				os.Exit(2)
+			}
+			opts.IgnoreTypes = append(opts.IgnoreTypes, n)
+		}
+	}
+
+	result := diff.CompareWith(before, after, opts)
+
+	output := os.Stdout
+	if *outputFile != "" {
+		output, err = os.Create(*outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(2)
+		}
+		defer output.Close()
+	}
+
+	switch strings.ToLower(*format) {
+	case "json":
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	default:
+		err = result.WriteText(output)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Exit status follows diff(1): 0 identical, 1 different, 2 error
+	if result.HasChanges() {
+		output.Close()
+		os.Exit(1)
+	}
+}
+
+// load reads a table from a dump file, or from the system for "live"
+func load(source string) (*gosmbios.SMBIOS, error) {
+	if source == liveSource {
+		return gosmbios.Read()
+	}
+	return gosmbios.ReadFromFile(source)
+}
+
+func printUsage() {
+	fmt.Println("smbiosdiff - Compare two SMBIOS tables")
+	fmt.Println()
+	fmt.Println("Usage: smbiosdiff [options] <before> [<after>]")
+	fmt.Println()
+	fmt.Println("Each table is a dump file (.smbios or QEMU blob) or \"live\" for the")
+	fmt.Println("running system. If <after> is omitted, the live system is used.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -o <file>      Output file path (default: stdout)")
+	fmt.Println("  -f <format>    Output format: text, json (default: text)")
+	fmt.Println("  -ignore <list> Comma-separated structure types to ignore (e.g. 15,18)")
+	fmt.Println("  -h             Show this help message")
+	fmt.Println()
+	fmt.Println("Exit status is 0 if the tables match, 1 if they differ, 2 on error.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  smbiosdiff before.smbios after.smbios      # Compare two dumps")
+	fmt.Println("  smbiosdiff before.smbios                   # Compare a dump with this system")
+	fmt.Println("  smbiosdiff -f json old.smbios new.smbios   # JSON report")
+}
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/fingerprint"
+	"github.com/earentir/gosmbios/libvirt"
+	"github.com/earentir/gosmbios/qemu"
 	"github.com/earentir/gosmbios/types"
 	"github.com/earentir/gosmbios/types/type0"
 	"github.com/earentir/gosmbios/types/type1"
@@ -63,42 +65,23 @@ import (
 	"github.com/earentir/gosmbios/types/type9"
 )
 
-// getSystemIdentifier returns a unique identifier for the system (UUID or serial)
+// getSystemIdentifier returns a unique identifier for the system (UUID or fingerprint)
 // suitable for use in a filename
 func getSystemIdentifier(sm *gosmbios.SMBIOS) string {
-	sys, err := type1.Get(sm)
-	if err != nil {
-		return ""
-	}
-
 	// Try UUID first (most unique)
-	if !sys.UUID.IsZero() && !sys.UUID.IsInvalid() {
+	if sys, err := type1.Get(sm); err == nil && sys.UUID.IsMeaningful() {
 		// Return UUID without dashes for cleaner filenames
 		return strings.ReplaceAll(sys.UUID.String(), "-", "")
 	}
 
-	// Fall back to serial number
-	if sys.SerialNumber != "" && sys.SerialNumber != "To Be Filled By O.E.M." {
-		return sanitizeFilename(sys.SerialNumber)
+	// Fall back to a fingerprint of the serial numbers and asset tag
+	if fp, err := fingerprint.Compute(sm); err == nil {
+		return "fp-" + fp.ID
 	}
 
 	return ""
 }
 
-// sanitizeFilename removes/replaces characters that are invalid in filenames
-func sanitizeFilename(s string) string {
-	// Remove or replace characters that are problematic in filenames
-	re := regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
-	s = re.ReplaceAllString(s, "_")
-	// Trim spaces and dots from ends
-	s = strings.Trim(s, " .")
-	// Limit length
-	if len(s) > 64 {
-		s = s[:64]
-	}
-	return s
-}
-
 // OutputFormat represents the output format type
 type OutputFormat string
 
@@ -107,6 +90,10 @@ const (
 	FormatJSON OutputFormat = "json"
 	FormatRaw  OutputFormat = "raw"
 	FormatBin  OutputFormat = "bin"
+
+	FormatQEMU     OutputFormat = "qemu"
+	FormatQEMUBlob OutputFormat = "qemu-blob"
+	FormatLibvirt  OutputFormat = "libvirt"
 )
 
 // SMBIOSDump represents the complete SMBIOS dump for JSON export
@@ -148,7 +135,7 @@ func main() {
 	// Command line flags
 	outputFile := flag.String("o", "", "Output file path (default: stdout)")
 	inputFile := flag.String("i", "", "Input file (gosmbios dump format) - read from dump instead of system")
-	format := flag.String("f", "text", "Output format: text, json, raw, bin")
+	format := flag.String("f", "text", "Output format: text, json, raw, bin, qemu, qemu-blob, libvirt")
 	showHelp := flag.Bool("h", false, "Show help")
 	flag.Parse()
 
@@ -221,6 +208,37 @@ func main() {
 		return
 	}
 
+	// QEMU blob is binary too (structures only, no file header)
+	if OutputFormat(strings.ToLower(*format)) == FormatQEMUBlob {
+		blobFile := *outputFile
+		if blobFile == "" {
+			identifier := getSystemIdentifier(sm)
+			if identifier == "" {
+				identifier = time.Now().Format("20060102-150405")
+			}
+			blobFile = identifier + ".bin"
+		}
+
+		dir := filepath.Dir(blobFile)
+		if dir != "." && dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := os.WriteFile(blobFile, qemu.Blob(sm, qemu.BlobOptions{}), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing QEMU blob: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "QEMU SMBIOS blob written to: %s\n", blobFile)
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Use with: qemu-system-x86_64 -smbios file=%s ...\n", blobFile)
+		fmt.Fprintf(os.Stderr, "Read with: smbiosdump -i %s\n", blobFile)
+		return
+	}
+
 	// Determine output writer for text-based formats
 	var output *os.File
 	if *outputFile != "" {
@@ -249,6 +267,13 @@ func main() {
 		err = dumpJSON(sm, output)
 	case FormatRaw:
 		err = dumpRaw(sm, output)
+	case FormatQEMU:
+		_, err = fmt.Fprintln(output, qemu.CommandLine(sm))
+	case FormatLibvirt:
+		var data []byte
+		if data, err = libvirt.FromSMBIOS(sm).Marshal(); err == nil {
+			_, err = fmt.Fprintln(output, string(data))
+		}
 	default:
 		err = dumpText(sm, output)
 	}
@@ -270,8 +295,8 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -o <file>   Output file path (default: auto-named for bin, stdout for others)")
-	fmt.Println("  -i <file>   Input file (.smbios dump) - read from dump instead of system")
-	fmt.Println("  -f <format> Output format: text, json, raw, bin (default: text)")
+	fmt.Println("  -i <file>   Input file (.smbios dump or QEMU blob) - read from dump instead of system")
+	fmt.Println("  -f <format> Output format: text, json, raw, bin, qemu, qemu-blob, libvirt (default: text)")
 	fmt.Println("  -h          Show this help message")
 	fmt.Println()
 	fmt.Println("Formats:")
@@ -281,6 +306,10 @@ func printUsage() {
 	fmt.Println("  bin         Raw binary dump - stores SMBIOS table exactly as in memory")
 	fmt.Println("              Auto-names file as <UUID>.smbios if -o not specified")
 	fmt.Println("              Preserves ALL data including unknown/future types")
+	fmt.Println("  qemu        QEMU -smbios type=N,... arguments to clone this system's identity")
+	fmt.Println("  qemu-blob   Binary blob for QEMU -smbios file=<blob>")
+	fmt.Println("              Auto-names file as <UUID>.bin if -o not specified")
+	fmt.Println("  libvirt     libvirt domain <sysinfo type='smbios'> XML block")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  smbiosdump -f bin                        # Auto-named: <UUID>.smbios")
@@ -289,6 +318,9 @@ func printUsage() {
 	fmt.Println("  smbiosdump -o smbios.json -f json        # Dump as JSON")
 	fmt.Println("  smbiosdump -i 4C4C4544.smbios            # Read from dump file")
 	fmt.Println("  smbiosdump -i dump.smbios -f json        # Convert dump to JSON")
+	fmt.Println("  smbiosdump -f qemu                       # QEMU -smbios arguments")
+	fmt.Println("  smbiosdump -o host.bin -f qemu-blob      # QEMU -smbios file= blob")
+	fmt.Println("  smbiosdump -f libvirt                    # libvirt <sysinfo> XML")
 	fmt.Println()
 	fmt.Println("The binary format (-f bin) is recommended for archiving SMBIOS data.")
 	fmt.Println("Files are named using the system's UUID for easy identification.")
@@ -380,7 +412,9 @@ func dumpText(sm *gosmbios.SMBIOS, w *os.File) error {
 	printType45Text(sm, w)
 	printType46Text(sm, w)
 
-	// Print unknown types
+	// Print inactive, OEM-specific and unknown types
+	printInactiveText(sm, w)
+	printOEMText(sm, w)
 	printUnknownTypesText(sm, w, typeCounts)
 
 	fmt.Fprintln(w)
@@ -650,7 +684,13 @@ func printType17Text(sm *gosmbios.SMBIOS, w *os.File) {
 		fmt.Fprintf(w, "  Type:           %s\n", dev.MemoryType.String())
 		fmt.Fprintf(w, "  Speed:          %s\n", dev.SpeedString())
 		fmt.Fprintf(w, "  Manufacturer:   %s\n", dev.Manufacturer)
+		if vendor := dev.Vendor(); vendor != "" && vendor != dev.Manufacturer {
+			fmt.Fprintf(w, "  Vendor:         %s\n", vendor)
+		}
 		fmt.Fprintf(w, "  Part Number:    %s\n", dev.PartNumber)
+		if label := dev.JEDECLabel(); label != "" {
+			fmt.Fprintf(w, "  Module Label:   %s\n", label)
+		}
 	}
 }
 
@@ -876,6 +916,7 @@ func printType38Text(sm *gosmbios.SMBIOS, w *os.File) {
 	fmt.Fprintf(w, "Interface Type: %s\n", ipmi.InterfaceType.String())
 	fmt.Fprintf(w, "Spec Revision:  %s\n", ipmi.SpecificationRevisionString())
 	fmt.Fprintf(w, "Base Address:   %s\n", ipmi.BaseAddressString())
+	fmt.Fprintf(w, "Interrupt:      %s\n", ipmi.InterruptNumberString())
 }
 
 func printType39Text(sm *gosmbios.SMBIOS, w *os.File) {
@@ -922,6 +963,12 @@ func printType42Text(sm *gosmbios.SMBIOS, w *os.File) {
 	fmt.Fprintln(w, "\n--- Type 42: Management Controller Host Interface ---")
 	for i, mchi := range mchis {
 		fmt.Fprintf(w, "Interface %d: %s, Protocols: %d\n", i+1, mchi.InterfaceType.String(), len(mchi.ProtocolRecords))
+		if mchi.NetworkDevice != nil {
+			fmt.Fprintf(w, "  Device:         %s\n", mchi.NetworkDevice)
+		}
+		for _, rf := range mchi.RedfishRecords() {
+			fmt.Fprintf(w, "  Redfish:        host %s (%s), service %s:%d (%s) %s\n", rf.HostIP, rf.HostIPAssignment, rf.ServiceIP, rf.ServicePort, rf.ServiceIPDiscovery, rf.ServiceHostname)
+		}
 	}
 }
 
@@ -945,6 +992,11 @@ func printType44Text(sm *gosmbios.SMBIOS, w *os.File) {
 	fmt.Fprintln(w, "\n--- Type 44: Processor Additional Information ---")
 	for i, info := range infos {
 		fmt.Fprintf(w, "Info %d: Handle 0x%04X, Type: %s\n", i+1, info.ReferencedHandle, info.ProcessorSpecificBlock.ProcessorType.String())
+		if rv, err := info.ProcessorSpecificBlock.RISCV(); err == nil {
+			fmt.Fprintf(w, "  ISA:            %s\n", rv.ISAString())
+			fmt.Fprintf(w, "  Hart ID:        %s (boot hart: %v)\n", rv.HartID, rv.BootHart)
+			fmt.Fprintf(w, "  IDs:            vendor %s, arch %s, impl %s\n", rv.MachineVendorID, rv.MachineArchID, rv.MachineImplID)
+		}
 	}
 }
 
@@ -973,6 +1025,46 @@ func printType46Text(sm *gosmbios.SMBIOS, w *os.File) {
 	}
 }
 
+func printInactiveText(sm *gosmbios.SMBIOS, w *os.File) {
+	structs := sm.InactiveStructures()
+	if len(structs) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\n--- Type 126: Inactive ---")
+	for i, s := range structs {
+		fmt.Fprintf(w, "  [%d] Handle: 0x%04X, Length: %d\n", i, s.Header.Handle, s.Header.Length)
+		for _, t := range s.CandidateTypes() {
+			fmt.Fprintf(w, "      Possibly: Type %d - %s\n", t, types.TypeName(t))
+		}
+		fmt.Fprintf(w, "      Data: %s\n", hex.EncodeToString(s.Data))
+		for j, str := range s.Strings {
+			fmt.Fprintf(w, "      String[%d]: %q\n", j+1, str)
+		}
+	}
+}
+
+func printOEMText(sm *gosmbios.SMBIOS, w *os.File) {
+	oems := sm.OEMStructures()
+	if len(oems) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\n--- OEM-specific Types ---")
+	if oems[0].Vendor != "" {
+		fmt.Fprintf(w, "Vendor: %s\n", oems[0].Vendor)
+	}
+	for _, oem := range oems {
+		name := oem.TypeName
+		if name == "" {
+			name = "Unknown"
+		}
+		fmt.Fprintf(w, "Type %3d: Handle: 0x%04X, Length: %d - %s\n", oem.Header.Type, oem.Header.Handle, oem.Header.Length, name)
+		fmt.Fprintf(w, "      Data: %s\n", hex.EncodeToString(oem.Data))
+		for j, str := range oem.Strings {
+			fmt.Fprintf(w, "      String[%d]: %q\n", j+1, str)
+		}
+	}
+}
+
 func printUnknownTypesText(sm *gosmbios.SMBIOS, w *os.File, typeCounts map[uint8]int) {
 	handled := map[uint8]bool{
 		0: true, 1: true, 2: true, 3: true, 4: true, 5: true, 6: true,
@@ -983,12 +1075,12 @@ func printUnknownTypesText(sm *gosmbios.SMBIOS, w *os.File, typeCounts map[uint8
 		27: true, 28: true, 29: true, 30: true, 31: true, 32: true,
 		33: true, 34: true, 35: true, 36: true, 37: true,
 		38: true, 39: true, 40: true, 41: true, 42: true, 43: true,
-		44: true, 45: true, 46: true, 127: true,
+		44: true, 45: true, 46: true, 126: true, 127: true,
 	}
 
 	hasUnknown := false
 	for t := range typeCounts {
-		if !handled[t] {
+		if !handled[t] && t < gosmbios.OEMTypeMin {
 			hasUnknown = true
 			break
 		}
@@ -998,8 +1090,8 @@ func printUnknownTypesText(sm *gosmbios.SMBIOS, w *os.File, typeCounts map[uint8
 		return
 	}
 
-	fmt.Fprintln(w, "\n--- Unknown/OEM Types ---")
-	for t := uint8(0); t <= 255; t++ {
+	fmt.Fprintln(w, "\n--- Unknown Types ---")
+	for t := uint8(0); t < gosmbios.OEMTypeMin; t++ {
 		if count, ok := typeCounts[t]; ok && !handled[t] {
 			fmt.Fprintf(w, "Type %3d: %d structure(s) - %s\n", t, count, types.TypeName(t))
 			// Print raw data for unknown types
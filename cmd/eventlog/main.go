@@ -0,0 +1,176 @@
+// smbioseventlog - Tool to decode the System Event Log area described by SMBIOS
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type15"
+)
+
+// record is the JSON form of an event record
+type record struct {
+	Offset      int      `json:"offset"`
+	Time        string   `json:"time,omitempty"`
+	Type        uint8    `json:"type"`
+	Description string   `json:"description"`
+	Format      string   `json:"format"`
+	Handle      *uint16  `json:"handle,omitempty"`
+	Counter     *uint32  `json:"counter,omitempty"`
+	POSTErrors  []string `json:"post_errors,omitempty"`
+	SystemEvent string   `json:"system_management_event,omitempty"`
+	Read        bool     `json:"read"`
+	Data        string   `json:"data,omitempty"`
+}
+
+func main() {
+	inputFile := flag.String("i", "", "Input file (gosmbios dump format) - read from dump instead of system")
+	areaFile := flag.String("a", "", "Log area image (memory-mapped area or indexed I/O dump)")
+	memFile := flag.String("m", "", "Physical memory image holding the memory-mapped log area")
+	baseAddr := flag.String("base", "0", "Physical address of the first byte of the memory image")
+	format := flag.String("f", "text", "Output format: text, json")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp {
+		printUsage()
+		os.Exit(0)
+	}
+
+	var sm *gosmbios.SMBIOS
+	var err error
+	if *inputFile != "" {
+		sm, err = gosmbios.ReadFromFile(*inputFile)
+	} else {
+		sm, err = gosmbios.Read()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading SMBIOS: %v\n", err)
+		os.Exit(1)
+	}
+
+	sel, err := type15.Get(sm)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no System Event Log found: %v\n", err)
+		os.Exit(1)
+	}
+
+	area, err := readArea(sel, *areaFile, *memFile, *baseAddr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading log area: %v\n", err)
+		os.Exit(1)
+	}
+
+	lg, err := sel.DecodeLog(area)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error decoding log area: %v\n", err)
+		os.Exit(1)
+	}
+
+	if strings.ToLower(*format) == "json" {
+		records := make([]record, 0, len(lg.Records))
+		for _, r := range lg.Records {
+			records = append(records, toJSON(r))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(records); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Log Status:    %s\n", sel.LogStatus)
+	fmt.Printf("Change Token:  0x%08X\n", lg.ChangeToken)
+	if h := lg.Header; h != nil {
+		fmt.Printf("Header:        Type 1 revision %d, multiple event window %d min, count increment %d\n",
+			h.Revision, h.MultipleEventTimeWindow, h.MultipleEventCountIncrement)
+	}
+	fmt.Printf("Records:       %d\n\n", len(lg.Records))
+	for _, r := range lg.Records {
+		fmt.Println(r.String())
+	}
+}
+
+// readArea reads the log area from an area image, a memory image, or, for
+// memory-mapped logs on the running system, /dev/mem
+func readArea(sel *type15.SystemEventLog, areaFile, memFile, baseAddr string) ([]byte, error) {
+	if areaFile != "" {
+		return os.ReadFile(areaFile)
+	}
+
+	base, err := strconv.ParseUint(baseAddr, 0, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base address %q", baseAddr)
+	}
+	if memFile == "" {
+		if sel.AccessMethod != type15.AccessMemoryMapped32Bit {
+			return nil, fmt.Errorf("%s access needs a log area image (-a)", sel.AccessMethod)
+		}
+		memFile, base = "/dev/mem", 0
+	}
+
+	f, err := os.Open(memFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return sel.ReadLogArea(f, base)
+}
+
+func toJSON(r type15.Record) record {
+	out := record{
+		Offset:      r.Offset,
+		Type:        uint8(r.Type),
+		Description: r.Type.String(),
+		Format:      r.Format.String(),
+		Read:        r.Read,
+	}
+	if r.TimeValid {
+		out.Time = r.Time.Format("2006-01-02T15:04:05")
+	}
+	if r.HasHandle() {
+		handle := r.Handle
+		out.Handle = &handle
+	}
+	if r.HasCounter() {
+		counter := r.Counter
+		out.Counter = &counter
+	}
+	switch r.Format {
+	case type15.VarDataPOSTResults:
+		out.POSTErrors = r.POSTResults.Errors()
+	case type15.VarDataSystemManagement, type15.VarDataMultipleSystemManagement:
+		out.SystemEvent = r.SystemManagementType.String()
+	}
+	if len(r.Data) > 0 {
+		out.Data = fmt.Sprintf("%x", r.Data)
+	}
+	return out
+}
+
+func printUsage() {
+	fmt.Println("smbioseventlog - Decode the System Event Log described by SMBIOS")
+	fmt.Println()
+	fmt.Println("Usage: smbioseventlog [options]")
+	fmt.Println()
+	fmt.Println("Decodes the event records of the log area described by the System Event")
+	fmt.Println("Log (Type 15) structure, such as BIOS-logged ECC and POST errors.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -i <file>    Read the table from a gosmbios dump file instead of the system")
+	fmt.Println("  -a <file>    Log area image: the memory-mapped area, or the bytes read")
+	fmt.Println("               through the index/data ports from index 0")
+	fmt.Println("  -m <file>    Physical memory image holding a memory-mapped log area")
+	fmt.Println("  -base <addr> Physical address of the first byte of the image (default: 0)")
+	fmt.Println("  -f <format>  Output format: text, json (default: text)")
+	fmt.Println("  -h           Show this help message")
+	fmt.Println()
+	fmt.Println("Without -a or -m a memory-mapped log is read from /dev/mem (root required).")
+}
@@ -0,0 +1,107 @@
+// smbiosfleet - Tool to aggregate SMBIOS dumps from many hosts into fleet reports
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/gosmbios/fleet"
+)
+
+func main() {
+	outputFile := flag.String("o", "", "Output file path (default: stdout)")
+	format := flag.String("f", "text", "Output format: text, json, csv, csv-counts")
+	workers := flag.Int("j", 0, "Number of dumps parsed in parallel (default: number of CPUs)")
+	top := flag.Int("top", 10, "Entries per histogram in text output (0 for all)")
+	extensions := flag.String("ext", ".smbios", "Comma-separated dump file extensions")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp || flag.NArg() == 0 {
+		printUsage()
+		if *showHelp {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+
+	opts := fleet.Options{
+		Workers: *workers,
+		OnError: func(err *fleet.FileError) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		},
+	}
+	for _, ext := range strings.Split(*extensions, ",") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			opts.Extensions = append(opts.Extensions, ext)
+		}
+	}
+
+	report, scanErr := fleet.Scan(flag.Args(), opts)
+	if scanErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", scanErr)
+	}
+	fmt.Fprintf(os.Stderr, "Read %d dump(s), %d error(s)\n", len(report.Hosts), len(report.Errors))
+
+	var err error
+	output := os.Stdout
+	if *outputFile != "" {
+		output, err = os.Create(*outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer output.Close()
+	}
+
+	switch strings.ToLower(*format) {
+	case "json":
+		err = report.WriteJSON(output)
+	case "csv":
+		err = report.WriteHostsCSV(output)
+	case "csv-counts":
+		err = report.WriteCountsCSV(output)
+	default:
+		err = report.WriteText(output, *top)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Paths that could not be opened at all fail the run
+	if scanErr != nil {
+		output.Close()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("smbiosfleet - Aggregate SMBIOS dumps into fleet reports")
+	fmt.Println()
+	fmt.Println("Usage: smbiosfleet [options] <dir|archive|file>...")
+	fmt.Println()
+	fmt.Println("Directories are walked recursively. Archives may be .zip, .tar, .tar.gz")
+	fmt.Println("or .tgz. Unreadable dumps are reported on stderr and skipped.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -o <file>     Output file path (default: stdout)")
+	fmt.Println("  -f <format>   Output format: text, json, csv, csv-counts (default: text)")
+	fmt.Println("  -j <n>        Number of dumps parsed in parallel (default: number of CPUs)")
+	fmt.Println("  -top <n>      Entries per histogram in text output, 0 for all (default: 10)")
+	fmt.Println("  -ext <list>   Comma-separated dump file extensions (default: .smbios)")
+	fmt.Println("  -h            Show this help message")
+	fmt.Println()
+	fmt.Println("Formats:")
+	fmt.Println("  text          Summary with counts by manufacturer, model, BIOS, CPU,")
+	fmt.Println("                memory per host, DIMM part numbers and TPM presence")
+	fmt.Println("  json          Summary plus per-host details and errors")
+	fmt.Println("  csv           One row per host")
+	fmt.Println("  csv-counts    Histograms as category,value,count rows")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  smbiosfleet /srv/dumps                     # Text summary")
+	fmt.Println("  smbiosfleet -f csv -o hosts.csv dumps.tgz  # Per-host CSV from an archive")
+}
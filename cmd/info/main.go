@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/hypervisor"
 	"github.com/earentir/gosmbios/types"
 	"github.com/earentir/gosmbios/types/type0"
 	"github.com/earentir/gosmbios/types/type1"
@@ -155,6 +156,8 @@ func main() {
 	printFirmwareInventory(sm)
 	printStringProperties(sm)
 	printEndOfTable(sm)
+	printInactive(sm)
+	printOEMStructures(sm)
 	printUnknownTypes(sm, typeCounts)
 
 	fmt.Println("\n================================================================================")
@@ -220,6 +223,13 @@ func printSystem(sm *gosmbios.SMBIOS) {
 	fmt.Printf("  Wake-up Type:           %s\n", sys.WakeUpType.String())
 	fmt.Printf("  SKU Number:             %s\n", sys.SKUNumber)
 	fmt.Printf("  Family:                 %s\n", sys.Family)
+	if virt := hypervisor.Detect(sm); virt.IsVirtual {
+		if virt.Version != "" {
+			fmt.Printf("  Virtualization:         %s (%s)\n", virt.Hypervisor, virt.Version)
+		} else {
+			fmt.Printf("  Virtualization:         %s\n", virt.Hypervisor)
+		}
+	}
 	fmt.Println()
 }
 
@@ -312,6 +322,22 @@ func printProcessors(sm *gosmbios.SMBIOS) {
 		fmt.Printf("    Family:               %s\n", proc.ProcessorFamily.String())
 		fmt.Printf("    Manufacturer:         %s\n", proc.ProcessorManufacturer)
 		fmt.Printf("    Version:              %s\n", proc.ProcessorVersion)
+		if id, ok := proc.CPUID(); ok {
+			fmt.Printf("    Signature:            %s\n", id.String())
+			if uarch := proc.Microarchitecture(); uarch != "" {
+				fmt.Printf("    Microarchitecture:    %s\n", uarch)
+			}
+			fmt.Printf("    Flags:                %s\n", id.Features.String())
+		}
+		if midr, ok := proc.MIDR(); ok {
+			fmt.Printf("    MIDR:                 %s\n", midr.String())
+		}
+		if soc, ok := proc.SoCID(); ok {
+			fmt.Printf("    SoC ID:               %s\n", soc.String())
+			if core := soc.Core(); core != "" {
+				fmt.Printf("    Core:                 %s\n", core)
+			}
+		}
 		fmt.Printf("    Voltage:              %s\n", proc.Voltage.String())
 		fmt.Printf("    External Clock:       %d MHz\n", proc.ExternalClock)
 		fmt.Printf("    Max Speed:            %d MHz\n", proc.MaxSpeed)
@@ -474,8 +500,13 @@ func printOEMStrings(sm *gosmbios.SMBIOS) {
 	fmt.Println("Type 11: OEM Strings")
 	fmt.Println("================================================================================")
 	for _, oem := range oems {
-		for i, str := range oem.Strings {
-			fmt.Printf("  String %d: %s\n", i+1, str)
+		for i, e := range oem.Entries() {
+			// Credentials may hold secrets, so only their name and size are shown
+			if e.Kind == type11.EntryCredential {
+				fmt.Printf("  String %d: [credential %s, %d bytes]\n", i+1, e.Key, len(e.Data))
+				continue
+			}
+			fmt.Printf("  String %d: %s\n", i+1, e.Raw)
 		}
 	}
 	fmt.Println()
@@ -547,7 +578,10 @@ func printEventLog(sm *gosmbios.SMBIOS) {
 	fmt.Printf("  Log Header Start:       0x%04X\n", log.LogHeaderStartOffset)
 	fmt.Printf("  Log Data Start:         0x%04X\n", log.LogDataStartOffset)
 	fmt.Printf("  Access Method:          %s\n", log.AccessMethod.String())
+	fmt.Printf("  Access Address:         0x%08X\n", log.AccessMethodAddress)
 	fmt.Printf("  Log Status:             Full: %v, Valid: %v\n", log.LogStatus.IsFull(), log.LogStatus.IsValid())
+	fmt.Printf("  Change Token:           0x%08X\n", log.LogChangeToken)
+	fmt.Printf("  Header Format:          %s\n", log.LogHeaderFormat.String())
 	fmt.Printf("  Supported Log Types:    %d\n", log.NumberOfSupportedLogTypes)
 	fmt.Println()
 }
@@ -594,10 +628,25 @@ func printMemoryDevices(sm *gosmbios.SMBIOS) {
 		fmt.Printf("    Type Detail:          %s\n", dev.TypeDetail.String())
 		fmt.Printf("    Speed:                %s\n", dev.SpeedString())
 		fmt.Printf("    Manufacturer:         %s\n", dev.Manufacturer)
+		if vendor := dev.Vendor(); vendor != "" && vendor != dev.Manufacturer {
+			fmt.Printf("    Vendor:               %s\n", vendor)
+		}
 		fmt.Printf("    Serial Number:        %s\n", dev.SerialNumber)
 		fmt.Printf("    Part Number:          %s\n", dev.PartNumber)
+		if label := dev.JEDECLabel(); label != "" {
+			fmt.Printf("    Module Label:         %s\n", label)
+		}
 		fmt.Printf("    Configured Speed:     %d MT/s\n", dev.GetConfiguredSpeed())
 		fmt.Printf("    Voltage:              %s\n", dev.VoltageString())
+		if name := dev.MemorySubsystemControllerManufacturer(); name != "" {
+			fmt.Printf("    Controller Vendor:    %s\n", name)
+		}
+		if name := dev.PMIC0Manufacturer(); name != "" {
+			fmt.Printf("    PMIC0:                %s, revision %s\n", name, dev.PMIC0RevisionString())
+		}
+		if name := dev.RCDManufacturer(); name != "" {
+			fmt.Printf("    RCD:                  %s, revision %s\n", name, dev.RCDRevisionString())
+		}
 		fmt.Println()
 		totalSize += dev.Size
 	}
@@ -979,7 +1028,13 @@ func printIPMI(sm *gosmbios.SMBIOS) {
 	fmt.Printf("  Specification Version:  %s\n", ipmi.SpecificationRevisionString())
 	fmt.Printf("  I2C Slave Address:      %s\n", ipmi.I2CAddressString())
 	fmt.Printf("  Base Address:           %s\n", ipmi.BaseAddressString())
+	if ipmi.InterfaceType != type38.InterfaceTypeSSIF {
+		fmt.Printf("  Register Spacing:       %d byte(s)\n", ipmi.RegisterSpacingBytes())
+	}
 	fmt.Printf("  Interrupt:              %s\n", ipmi.InterruptNumberString())
+	if opts, err := ipmi.ModprobeOptions(); err == nil {
+		fmt.Printf("  Driver Options:         %s", opts)
+	}
 	fmt.Println()
 }
 
@@ -1061,9 +1116,20 @@ func printMCHI(sm *gosmbios.SMBIOS) {
 	for i, mchi := range mchis {
 		fmt.Printf("  Interface %d:\n", i+1)
 		fmt.Printf("    Interface Type:       %s\n", mchi.InterfaceType.String())
+		if mchi.NetworkDevice != nil {
+			fmt.Printf("    Device:               %s\n", mchi.NetworkDevice)
+		}
 		fmt.Printf("    Protocol Records:     %d\n", len(mchi.ProtocolRecords))
 		for j, pr := range mchi.ProtocolRecords {
 			fmt.Printf("    Protocol %d: %s\n", j, pr.ProtocolType.String())
+			if rf, err := pr.RedfishOverIP(); err == nil {
+				fmt.Printf("      Service UUID:       %s\n", rf.ServiceUUID)
+				fmt.Printf("      Host IP:            %s/%s (%s)\n", rf.HostIP, rf.HostMask, rf.HostIPAssignment)
+				fmt.Printf("      Service IP:         %s/%s (%s)\n", rf.ServiceIP, rf.ServiceMask, rf.ServiceIPDiscovery)
+				fmt.Printf("      Service Port:       %d\n", rf.ServicePort)
+				fmt.Printf("      Service VLAN:       %d\n", rf.ServiceVLAN)
+				fmt.Printf("      Service Hostname:   %s\n", rf.ServiceHostname)
+			}
 		}
 	}
 	fmt.Println()
@@ -1099,7 +1165,21 @@ func printProcessorAdditional(sm *gosmbios.SMBIOS) {
 	for i, info := range infos {
 		fmt.Printf("  Info %d:\n", i+1)
 		fmt.Printf("    Referenced Handle:    0x%04X\n", info.ReferencedHandle)
+		if proc, err := info.Processor(sm); err == nil {
+			fmt.Printf("    Processor:            %s\n", proc.SocketDesignation)
+		}
 		fmt.Printf("    Processor Type:       %s\n", info.ProcessorSpecificBlock.ProcessorType.String())
+		if rv, err := info.ProcessorSpecificBlock.RISCV(); err == nil {
+			fmt.Printf("    ISA:                  %s\n", rv.ISAString())
+			fmt.Printf("    Hart ID:              %s\n", rv.HartID)
+			fmt.Printf("    Boot Hart:            %v\n", rv.BootHart)
+			fmt.Printf("    Privilege Levels:     %s\n", rv.PrivilegeLevels)
+			fmt.Printf("    Vendor ID:            %s\n", rv.MachineVendorID)
+			fmt.Printf("    Architecture ID:      %s\n", rv.MachineArchID)
+			fmt.Printf("    Implementation ID:    %s\n", rv.MachineImplID)
+		} else if la, err := info.ProcessorSpecificBlock.LoongArch(); err == nil {
+			fmt.Printf("    Block Revision:       %s\n", la.RevisionString())
+		}
 	}
 	fmt.Println()
 }
@@ -1157,6 +1237,47 @@ func printEndOfTable(sm *gosmbios.SMBIOS) {
 	fmt.Println()
 }
 
+func printInactive(sm *gosmbios.SMBIOS) {
+	structs := sm.InactiveStructures()
+	if len(structs) == 0 {
+		return
+	}
+
+	fmt.Println("================================================================================")
+	fmt.Println("Type 126: Inactive Structures")
+	fmt.Println("================================================================================")
+	for _, s := range structs {
+		fmt.Printf("  Handle 0x%04X, Length %d\n", s.Header.Handle, s.Header.Length)
+		for _, t := range s.CandidateTypes() {
+			fmt.Printf("    Possibly:             Type %d - %s\n", t, types.TypeName(t))
+		}
+	}
+	fmt.Println()
+}
+
+func printOEMStructures(sm *gosmbios.SMBIOS) {
+	oems := sm.OEMStructures()
+	if len(oems) == 0 {
+		return
+	}
+
+	fmt.Println("================================================================================")
+	fmt.Println("OEM-specific Structures")
+	fmt.Println("================================================================================")
+	if oems[0].Vendor != "" {
+		fmt.Printf("  Vendor:                 %s\n", oems[0].Vendor)
+	}
+	for _, oem := range oems {
+		name := oem.TypeName
+		if name == "" {
+			name = "Unknown"
+		}
+		fmt.Printf("  Type %3d: Handle 0x%04X, Length %3d - %s\n",
+			oem.Header.Type, oem.Header.Handle, oem.Header.Length, name)
+	}
+	fmt.Println()
+}
+
 func printUnknownTypes(sm *gosmbios.SMBIOS, typeCounts map[uint8]int) {
 	// Types we handle
 	handled := map[uint8]bool{
@@ -1168,12 +1289,12 @@ func printUnknownTypes(sm *gosmbios.SMBIOS, typeCounts map[uint8]int) {
 		27: true, 28: true, 29: true, 30: true, 31: true, 32: true,
 		33: true, 34: true, 35: true, 36: true, 37: true,
 		38: true, 39: true, 40: true, 41: true, 42: true, 43: true,
-		44: true, 45: true, 46: true, 127: true,
+		44: true, 45: true, 46: true, 126: true, 127: true,
 	}
 
 	hasUnknown := false
 	for t := range typeCounts {
-		if !handled[t] {
+		if !handled[t] && t < gosmbios.OEMTypeMin {
 			hasUnknown = true
 			break
 		}
@@ -1187,7 +1308,7 @@ func printUnknownTypes(sm *gosmbios.SMBIOS, typeCounts map[uint8]int) {
 	fmt.Println("Other Structures (not displayed in detail)")
 	fmt.Println("================================================================================")
 	for t := uint8(0); t <= 255; t++ {
-		if count, ok := typeCounts[t]; ok && !handled[t] {
+		if count, ok := typeCounts[t]; ok && !handled[t] && t < gosmbios.OEMTypeMin {
 			fmt.Printf("  Type %3d: %d structure(s) - %s\n", t, count, types.TypeName(t))
 		}
 		if t == 255 {
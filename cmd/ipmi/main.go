@@ -0,0 +1,109 @@
+// smbiosipmi - Tool to generate IPMI driver configuration from SMBIOS tables
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type38"
+)
+
+func main() {
+	format := flag.String("f", "text", "Output format: text, modprobe, cmdline, freeipmi")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp {
+		printUsage()
+		os.Exit(0)
+	}
+
+	var sm *gosmbios.SMBIOS
+	var err error
+	switch flag.NArg() {
+	case 0:
+		sm, err = gosmbios.Read()
+	case 1:
+		sm, err = gosmbios.ReadFromFile(flag.Arg(0))
+	default:
+		printUsage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading SMBIOS: %v\n", err)
+		os.Exit(1)
+	}
+
+	ipmi, err := type38.Get(sm)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no IPMI Device Information found: %v\n", err)
+		os.Exit(1)
+	}
+
+	var output string
+	switch strings.ToLower(*format) {
+	case "text":
+		output = describe(ipmi)
+	case "modprobe":
+		output, err = ipmi.ModprobeOptions()
+	case "cmdline":
+		output, err = ipmi.KernelParameters()
+		output += "\n"
+	case "freeipmi":
+		output, err = ipmi.FreeIPMIConfig()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating configuration: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
+
+// describe returns a summary of the interface
+func describe(ipmi *type38.IPMIDeviceInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Interface:         %s\n", ipmi.InterfaceType)
+	fmt.Fprintf(&b, "IPMI Version:      %s\n", ipmi.SpecificationRevisionString())
+	fmt.Fprintf(&b, "Base Address:      %s\n", ipmi.BaseAddressString())
+	if ipmi.InterfaceType != type38.InterfaceTypeSSIF {
+		fmt.Fprintf(&b, "Register Spacing:  %d byte(s)\n", ipmi.RegisterSpacingBytes())
+	}
+	if irq, ok := ipmi.Interrupt(); ok {
+		fmt.Fprintf(&b, "Interrupt:         IRQ %d (%s)\n", irq, ipmi.BaseAddressModifier)
+	} else {
+		fmt.Fprintf(&b, "Interrupt:         None\n")
+	}
+	fmt.Fprintf(&b, "I2C Slave Address: 0x%02X\n", ipmi.I2CSlaveAddress)
+	if module, err := ipmi.Module(); err == nil {
+		fmt.Fprintf(&b, "Kernel Driver:     %s\n", module)
+	}
+	return b.String()
+}
+
+func printUsage() {
+	fmt.Println("smbiosipmi - Generate IPMI driver configuration from SMBIOS tables")
+	fmt.Println()
+	fmt.Println("Usage: smbiosipmi [options] [<dump>]")
+	fmt.Println()
+	fmt.Println("Reads the IPMI Device Information (Type 38) from a dump file, or the")
+	fmt.Println("running system by default, for systems where the driver cannot find")
+	fmt.Println("the BMC through ACPI.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -f <format> Output format (default: text)")
+	fmt.Println("              text     - Interface summary")
+	fmt.Println("              modprobe - /etc/modprobe.d options for ipmi_si or ipmi_ssif")
+	fmt.Println("              cmdline  - Kernel command line parameters")
+	fmt.Println("              freeipmi - freeipmi.conf snippet")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  smbiosipmi -f modprobe > /etc/modprobe.d/ipmi.conf")
+	fmt.Println("  smbiosipmi -f freeipmi >> /etc/freeipmi/freeipmi.conf")
+}
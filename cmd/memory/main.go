@@ -0,0 +1,185 @@
+// smbiosmem - Tool to analyze the memory described by SMBIOS tables
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/memory"
+)
+
+// resolution is the JSON form of a resolved address
+type resolution struct {
+	Address  string   `json:"address"`
+	Locators []string `json:"locators,omitempty"`
+	Exact    bool     `json:"exact"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// output is the JSON output
+type output struct {
+	Addresses []resolution     `json:"addresses,omitempty"`
+	Findings  []memory.Finding `json:"findings,omitempty"`
+	Plan      *memory.Plan     `json:"plan,omitempty"`
+}
+
+func main() {
+	inputFile := flag.String("i", "", "Input file (gosmbios dump format) - read from dump instead of system")
+	granularity := flag.String("g", "0", "Interleave granularity in bytes, e.g. 64 (default: unknown)")
+	lint := flag.Bool("lint", false, "Check the memory configuration for problems")
+	plan := flag.Bool("plan", false, "Plan a memory upgrade for each socket")
+	format := flag.String("f", "text", "Output format: text, json")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp || (flag.NArg() == 0 && !*lint && !*plan) {
+		printUsage()
+		if *showHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+	jsonOutput := false
+	switch strings.ToLower(*format) {
+	case "text":
+	case "json":
+		jsonOutput = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		os.Exit(2)
+	}
+
+	var sm *gosmbios.SMBIOS
+	var err error
+	if *inputFile != "" {
+		sm, err = gosmbios.ReadFromFile(*inputFile)
+	} else {
+		sm, err = gosmbios.Read()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading SMBIOS: %v\n", err)
+		os.Exit(1)
+	}
+
+	topo, err := memory.Build(sm)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no memory devices found: %v\n", err)
+		os.Exit(1)
+	}
+	if topo.InterleaveGranularity, err = strconv.ParseUint(*granularity, 0, 64); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid granularity %q\n", *granularity)
+		os.Exit(2)
+	}
+
+	var out output
+	failed := false
+	for _, arg := range flag.Args() {
+		addr, err := strconv.ParseUint(arg, 0, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid address %q\n", arg)
+			failed = true
+			continue
+		}
+		r := resolution{Address: fmt.Sprintf("0x%X", addr)}
+		res, err := topo.Resolve(addr)
+		if err != nil {
+			r.Error = err.Error()
+			failed = true
+		} else {
+			r.Locators, r.Exact = res.Locators(), res.Exact
+		}
+		out.Addresses = append(out.Addresses, r)
+		if !jsonOutput {
+			if err != nil {
+				fmt.Printf("%s: %v\n", r.Address, err)
+			} else {
+				fmt.Println(res.String())
+			}
+		}
+	}
+
+	if *lint {
+		out.Findings = topo.Lint()
+		if len(out.Findings) > 0 {
+			failed = true
+		}
+		if !jsonOutput {
+			if len(out.Addresses) > 0 {
+				fmt.Println()
+			}
+			printFindings(out.Findings)
+		}
+	}
+
+	if *plan {
+		out.Plan = topo.Plan()
+		if !jsonOutput {
+			if len(out.Addresses) > 0 || *lint {
+				fmt.Println()
+			}
+			if err := out.Plan.WriteText(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+				os.Exit(2)
+			}
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printFindings prints one finding per paragraph with its affected slots
+func printFindings(findings []memory.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("No memory configuration problems found")
+		return
+	}
+	for _, f := range findings {
+		fmt.Printf("[%-7s] %s: %s\n", strings.ToUpper(string(f.Severity)), f.Check, f.Message)
+		for _, slot := range f.Slots {
+			fmt.Printf("            - %s\n", slot)
+		}
+	}
+}
+
+func printUsage() {
+	fmt.Println("smbiosmem - Analyze the memory described by SMBIOS tables")
+	fmt.Println()
+	fmt.Println("Usage: smbiosmem [options] [<address>...]")
+	fmt.Println()
+	fmt.Println("Resolves physical addresses, such as those in machine-check or EDAC")
+	fmt.Println("logs, to the memory devices (DIMM locators) that back them, using the")
+	fmt.Println("Type 16, 17, 19 and 20 structures, and checks and plans the memory")
+	fmt.Println("configuration.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -i <file>   Read from gosmbios dump file instead of system")
+	fmt.Println("  -g <bytes>  Interleave granularity, to pick the device within an")
+	fmt.Println("              interleave set (SMBIOS does not record it)")
+	fmt.Println("  -lint       Check for unbalanced channels and sockets, mixed modules,")
+	fmt.Println("              modules below their rated speed and ECC mismatches")
+	fmt.Println("  -plan       Plan an upgrade: free capacity, empty slots, compatible")
+	fmt.Println("              modules and channel balance for each socket")
+	fmt.Println("  -f <format> Output format: text, json (default: text)")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Addresses may be decimal or 0x-prefixed hexadecimal. \"(one of)\" marks")
+	fmt.Println("addresses that could not be narrowed to a single device or row.")
+	fmt.Println()
+	fmt.Println("Exit status is 0 if all addresses resolve and no problems are found,")
+	fmt.Println("1 otherwise, and 2 on usage errors.")
+}
@@ -0,0 +1,188 @@
+// smbiospolicy - Tool to check SMBIOS tables against hardware compliance rules
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/policy"
+)
+
+// liveSource reads the table from the running system
+const liveSource = "live"
+
+// exprList collects repeated -e flags
+type exprList []string
+
+func (e *exprList) String() string     { return strings.Join(*e, "; ") }
+func (e *exprList) Set(v string) error { *e = append(*e, v); return nil }
+
+func main() {
+	var exprs exprList
+	policyFile := flag.String("p", "", "JSON policy file")
+	flag.Var(&exprs, "e", "Rule expression (repeatable)")
+	format := flag.String("f", "text", "Output format: text, json")
+	extension := flag.String("ext", ".smbios", "Dump file extension when reading directories")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp || (*policyFile == "" && len(exprs) == 0) {
+		printUsage()
+		if *showHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	p := &policy.Policy{}
+	if *policyFile != "" {
+		var err error
+		p, err = policy.LoadPolicy(*policyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading policy %s: %v\n", *policyFile, err)
+			os.Exit(2)
+		}
+	}
+	for _, expr := range exprs {
+		rule, err := policy.NewRule("", expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error in expression %q: %v\n", expr, err)
+			os.Exit(2)
+		}
+		p.Rules = append(p.Rules, *rule)
+	}
+
+	sources, err := expand(flag.Args(), *extension)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var results []*policy.Result
+	failed := false
+	errored := false
+	for _, source := range sources {
+		sm, err := load(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", source, err)
+			errored = true
+			continue
+		}
+		result := p.Evaluate(sm)
+		result.Source = source
+		results = append(results, result)
+		if !result.Passed() {
+			failed = true
+		}
+	}
+
+	switch strings.ToLower(*format) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	default:
+		for _, result := range results {
+			if err = result.WriteText(os.Stdout); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Exit status: 0 all rules pass, 1 a rule failed, 2 error
+	switch {
+	case errored:
+		os.Exit(2)
+	case failed:
+		os.Exit(1)
+	}
+}
+
+// expand replaces directories with the dump files they contain; no
+// arguments means the running system
+func expand(args []string, extension string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{liveSource}, nil
+	}
+	var sources []string
+	for _, arg := range args {
+		if arg == liveSource {
+			sources = append(sources, arg)
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			sources = append(sources, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), extension) {
+				sources = append(sources, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// load reads a table from a dump file, or from the system for "live"
+func load(source string) (*gosmbios.SMBIOS, error) {
+	if source == liveSource {
+		return gosmbios.Read()
+	}
+	return gosmbios.ReadFromFile(source)
+}
+
+func printUsage() {
+	fmt.Println("smbiospolicy - Check SMBIOS tables against hardware compliance rules")
+	fmt.Println()
+	fmt.Println("Usage: smbiospolicy (-p <policy> | -e <expr>...) [options] [<dump|dir>...]")
+	fmt.Println()
+	fmt.Println("Each source is a dump file, a directory of dumps, or \"live\" for the")
+	fmt.Println("running system (the default).")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -p <file>   JSON policy file")
+	fmt.Println("  -e <expr>   Rule expression, may be repeated")
+	fmt.Println("  -f <format> Output format: text, json (default: text)")
+	fmt.Println("  -ext <ext>  Dump file extension when reading directories (default: .smbios)")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Expressions:")
+	fmt.Println("  typeN.Field[.Sub]          Field or method of every type N structure")
+	fmt.Println("  == != < <= > >=            Compare numbers, dates, or strings (case-insensitive)")
+	fmt.Println("  contains, matches          Substring and regular expression match")
+	fmt.Println("  and, or, not, ( )          Combine conditions")
+	fmt.Println("  present, count, sum, min,  Functions over paths")
+	fmt.Println("  max, any, all")
+	fmt.Println("  64GB, 512MB, 1TB           Sizes in megabytes (the unit of type17.Size)")
+	fmt.Println()
+	fmt.Println("Policy file example:")
+	fmt.Println(`  {"name": "baseline", "rules": [`)
+	fmt.Println(`    {"name": "TPM 2.0", "expr": "present(type43) and type43.IsTPM2_0"},`)
+	fmt.Println(`    {"name": "UEFI", "expr": "type0.IsUEFI"},`)
+	fmt.Println(`    {"name": "Memory", "expr": "sum(type17.Size) >= 64GB"},`)
+	fmt.Println(`    {"name": "Admin password", "type": 24, "field": "AdministratorPasswordStatus", "op": "==", "value": "Enabled"}`)
+	fmt.Println(`  ]}`)
+	fmt.Println()
+	fmt.Println("Exit status is 0 if all rules pass, 1 if any fails, 2 on error.")
+}
@@ -0,0 +1,187 @@
+// smbiosready - Tool to report OS hardware readiness from SMBIOS tables
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/readiness"
+)
+
+// liveSource reads the table from the running system
+const liveSource = "live"
+
+func main() {
+	profileNames := flag.String("p", readiness.Windows11.Name, "Comma-separated profile names")
+	profileFile := flag.String("P", "", "JSON file with additional profiles")
+	format := flag.String("f", "text", "Output format: text, json")
+	extension := flag.String("ext", ".smbios", "Dump file extension when reading directories")
+	listProfiles := flag.Bool("list", false, "List available profiles")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp {
+		printUsage()
+		os.Exit(0)
+	}
+
+	if *profileFile != "" {
+		loaded, err := readiness.LoadProfiles(*profileFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading profiles %s: %v\n", *profileFile, err)
+			os.Exit(2)
+		}
+		for _, p := range loaded {
+			readiness.RegisterProfile(p)
+		}
+	}
+
+	if *listProfiles {
+		for _, p := range readiness.Profiles() {
+			fmt.Printf("%-12s %s\n", p.Name, p.Description)
+		}
+		return
+	}
+
+	var profiles []*readiness.Profile
+	for _, name := range strings.Split(*profileNames, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		p, ok := readiness.LookupProfile(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown profile %q (see -list)\n", name)
+			os.Exit(2)
+		}
+		profiles = append(profiles, p)
+	}
+
+	sources, err := expand(flag.Args(), *extension)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var reports []*readiness.Report
+	notReady := false
+	errored := false
+	for _, source := range sources {
+		sm, err := load(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", source, err)
+			errored = true
+			continue
+		}
+		for _, p := range profiles {
+			report := readiness.Evaluate(sm, p)
+			report.Source = source
+			reports = append(reports, report)
+			if report.Status != readiness.Pass {
+				notReady = true
+			}
+		}
+	}
+
+	switch strings.ToLower(*format) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(reports)
+	default:
+		for _, report := range reports {
+			if err = report.WriteText(os.Stdout); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Exit status: 0 all ready, 1 not ready or undetermined, 2 error
+	switch {
+	case errored:
+		os.Exit(2)
+	case notReady:
+		os.Exit(1)
+	}
+}
+
+// expand replaces directories with the dump files they contain; no
+// arguments means the running system
+func expand(args []string, extension string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{liveSource}, nil
+	}
+	var sources []string
+	for _, arg := range args {
+		if arg == liveSource {
+			sources = append(sources, arg)
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			sources = append(sources, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), extension) {
+				sources = append(sources, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// load reads a table from a dump file, or from the system for "live"
+func load(source string) (*gosmbios.SMBIOS, error) {
+	if source == liveSource {
+		return gosmbios.Read()
+	}
+	return gosmbios.ReadFromFile(source)
+}
+
+func printUsage() {
+	fmt.Println("smbiosready - Report OS hardware readiness from SMBIOS tables")
+	fmt.Println()
+	fmt.Println("Usage: smbiosready [options] [<dump|dir>...]")
+	fmt.Println()
+	fmt.Println("Each source is a dump file, a directory of dumps, or \"live\" for the")
+	fmt.Println("running system (the default).")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -p <names>  Comma-separated profile names (default: windows11)")
+	fmt.Println("  -P <file>   JSON file with additional profiles")
+	fmt.Println("  -f <format> Output format: text, json (default: text)")
+	fmt.Println("  -ext <ext>  Dump file extension when reading directories (default: .smbios)")
+	fmt.Println("  -list       List available profiles")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Built-in profiles: windows11, rhel10 (x86-64-v3)")
+	fmt.Println()
+	fmt.Println("Profile file example:")
+	fmt.Println(`  {"name": "lab", "description": "Lab hosts", "require_64bit": true,`)
+	fmt.Println(`   "min_memory_mb": 65536, "min_tpm_version": 2, "require_uefi": true,`)
+	fmt.Println(`   "rules": [{"name": "Recent BIOS", "expr": "type0.ReleaseDate > \"2022-01-01\""}]}`)
+	fmt.Println()
+	fmt.Println("Requirements SMBIOS does not record are reported as unknown.")
+	fmt.Println("Exit status is 0 if all sources are ready, 1 if not ready or")
+	fmt.Println("undetermined, 2 on error.")
+}
@@ -0,0 +1,177 @@
+// smbiosredfish - Tool to configure the Redfish Host Interface from SMBIOS tables
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type42"
+)
+
+func main() {
+	format := flag.String("f", "text", "Output format: text, networkd, nm, hosts")
+	iface := flag.String("i", "", "Host interface name (default: match by MAC, PCI path or USB IDs)")
+	name := flag.String("n", "redfish", "Connection and file name")
+	outputDir := flag.String("o", "", "Write the configuration file to this directory instead of stdout")
+	showHelp := flag.Bool("h", false, "Show help")
+	flag.Parse()
+
+	if *showHelp {
+		printUsage()
+		os.Exit(0)
+	}
+
+	var sm *gosmbios.SMBIOS
+	var err error
+	switch flag.NArg() {
+	case 0:
+		sm, err = gosmbios.Read()
+	case 1:
+		sm, err = gosmbios.ReadFromFile(flag.Arg(0))
+	default:
+		printUsage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading SMBIOS: %v\n", err)
+		os.Exit(1)
+	}
+
+	hc, err := type42.FindHostConfig(sm)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no Redfish Host Interface found: %v\n", err)
+		os.Exit(1)
+	}
+	hc.Interface = *iface
+	hc.Name = *name
+
+	var output, fileName string
+	var files []configFile
+	mode := os.FileMode(0644)
+	switch strings.ToLower(*format) {
+	case "text":
+		output = describe(hc)
+	case "networkd":
+		output, err = hc.Networkd()
+		fileName = hc.NetworkdFileName()
+		if err == nil && hc.VLANInterface() != "" {
+			// The VLAN interface needs a .netdev and its own .network
+			var vlan string
+			vlan, err = hc.NetworkdVLAN()
+			files = []configFile{
+				{hc.NetworkdNetDevFileName(), hc.NetworkdNetDev()},
+				{hc.NetworkdVLANFileName(), vlan},
+			}
+		}
+	case "nm", "networkmanager":
+		output, err = hc.NetworkManager()
+		fileName = hc.NetworkManagerFileName()
+		mode = 0600
+	case "hosts":
+		output, err = hc.HostsEntry()
+		if err == nil && output == "" {
+			err = fmt.Errorf("the service address is not known in advance")
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating configuration: %v\n", err)
+		os.Exit(1)
+	}
+	files = append([]configFile{{fileName, output}}, files...)
+
+	if *outputDir == "" || fileName == "" {
+		for i, f := range files {
+			if i > 0 {
+				fmt.Printf("\n# %s\n", f.name)
+			}
+			fmt.Print(f.content)
+		}
+		return
+	}
+	for _, f := range files {
+		path := filepath.Join(*outputDir, f.name)
+		if err := os.WriteFile(path, []byte(f.content), mode); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+}
+
+// configFile is a generated configuration file
+type configFile struct {
+	name    string
+	content string
+}
+
+// describe returns a summary of the host interface and Redfish service
+func describe(hc *type42.HostConfig) string {
+	var b strings.Builder
+	if hc.Device != nil {
+		fmt.Fprintf(&b, "Device:            %s\n", hc.Device.Type)
+		fmt.Fprintf(&b, "                   %s\n", hc.Device)
+	}
+	r := hc.Redfish
+	fmt.Fprintf(&b, "Service UUID:      %s\n", r.ServiceUUID)
+	fmt.Fprintf(&b, "Host IP:           %s (%s)\n", addressString(r.HostIP, r.HostPrefixLength()), r.HostIPAssignment)
+	fmt.Fprintf(&b, "Service IP:        %s (%s)\n", addressString(r.ServiceIP, r.ServicePrefixLength()), r.ServiceIPDiscovery)
+	fmt.Fprintf(&b, "Service Port:      %d\n", r.ServicePort)
+	if r.ServiceVLAN != 0 {
+		fmt.Fprintf(&b, "Service VLAN:      %d\n", r.ServiceVLAN)
+	}
+	if r.ServiceHostname != "" {
+		fmt.Fprintf(&b, "Service Hostname:  %s\n", r.ServiceHostname)
+	}
+	if url := r.ServiceURL(); url != "" {
+		fmt.Fprintf(&b, "Service URL:       %s\n", url)
+	}
+	return b.String()
+}
+
+// addressString formats an address with its prefix length
+func addressString(ip net.IP, prefix int) string {
+	if ip == nil {
+		return "not set"
+	}
+	s := ip.String()
+	if prefix >= 0 {
+		s += fmt.Sprintf("/%d", prefix)
+	}
+	return s
+}
+
+func printUsage() {
+	fmt.Println("smbiosredfish - Configure the Redfish Host Interface from SMBIOS tables")
+	fmt.Println()
+	fmt.Println("Usage: smbiosredfish [options] [<dump>]")
+	fmt.Println()
+	fmt.Println("Reads the Network Host Interface (Type 42) and its Redfish over IP")
+	fmt.Println("protocol record from a dump file, or the running system by default.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -f <format> Output format (default: text)")
+	fmt.Println("              text     - Interface and service summary")
+	fmt.Println("              networkd - systemd-networkd .network unit, plus a .netdev and")
+	fmt.Println("                         .network for the VLAN interface when the")
+	fmt.Println("                         service is on a VLAN")
+	fmt.Println("              nm       - NetworkManager keyfile (a VLAN connection when the")
+	fmt.Println("                         service is on a VLAN)")
+	fmt.Println("              hosts    - /etc/hosts entry for the service hostname")
+	fmt.Println("  -i <name>   Host interface name (default: match by MAC, PCI path or USB IDs)")
+	fmt.Println("  -n <name>   Connection and file name (default: redfish)")
+	fmt.Println("  -o <dir>    Write the networkd or nm files to a directory, e.g.")
+	fmt.Println("              /etc/systemd/network or /etc/NetworkManager/system-connections")
+	fmt.Println("  -h          Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  smbiosredfish -f networkd -o /etc/systemd/network")
+	fmt.Println("  smbiosredfish -f hosts >> /etc/hosts")
+}
@@ -0,0 +1,157 @@
+// Package decode parses any structure into its typeN struct, for tools that
+// work across all types such as the diff and policy packages
+package decode
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type10"
+	"github.com/earentir/gosmbios/types/type11"
+	"github.com/earentir/gosmbios/types/type12"
+	"github.com/earentir/gosmbios/types/type13"
+	"github.com/earentir/gosmbios/types/type14"
+	"github.com/earentir/gosmbios/types/type15"
+	"github.com/earentir/gosmbios/types/type16"
+	"github.com/earentir/gosmbios/types/type17"
+	"github.com/earentir/gosmbios/types/type18"
+	"github.com/earentir/gosmbios/types/type19"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type20"
+	"github.com/earentir/gosmbios/types/type21"
+	"github.com/earentir/gosmbios/types/type22"
+	"github.com/earentir/gosmbios/types/type23"
+	"github.com/earentir/gosmbios/types/type24"
+	"github.com/earentir/gosmbios/types/type25"
+	"github.com/earentir/gosmbios/types/type26"
+	"github.com/earentir/gosmbios/types/type27"
+	"github.com/earentir/gosmbios/types/type28"
+	"github.com/earentir/gosmbios/types/type29"
+	"github.com/earentir/gosmbios/types/type3"
+	"github.com/earentir/gosmbios/types/type30"
+	"github.com/earentir/gosmbios/types/type31"
+	"github.com/earentir/gosmbios/types/type32"
+	"github.com/earentir/gosmbios/types/type33"
+	"github.com/earentir/gosmbios/types/type34"
+	"github.com/earentir/gosmbios/types/type35"
+	"github.com/earentir/gosmbios/types/type36"
+	"github.com/earentir/gosmbios/types/type37"
+	"github.com/earentir/gosmbios/types/type38"
+	"github.com/earentir/gosmbios/types/type39"
+	"github.com/earentir/gosmbios/types/type4"
+	"github.com/earentir/gosmbios/types/type40"
+	"github.com/earentir/gosmbios/types/type41"
+	"github.com/earentir/gosmbios/types/type42"
+	"github.com/earentir/gosmbios/types/type43"
+	"github.com/earentir/gosmbios/types/type44"
+	"github.com/earentir/gosmbios/types/type45"
+	"github.com/earentir/gosmbios/types/type46"
+	"github.com/earentir/gosmbios/types/type5"
+	"github.com/earentir/gosmbios/types/type6"
+	"github.com/earentir/gosmbios/types/type7"
+	"github.com/earentir/gosmbios/types/type8"
+	"github.com/earentir/gosmbios/types/type9"
+)
+
+// ErrNoDecoder is returned for structure types without a decoder, such as
+// unregistered OEM types
+var ErrNoDecoder = errors.New("smbios: no decoder for structure type")
+
+// Decoder parses a structure into its typeN struct
+type Decoder func(s *gosmbios.Structure) (any, error)
+
+// dec adapts a typeN Parse function to a decoder
+func dec[T any](parse func(*gosmbios.Structure) (*T, error)) Decoder {
+	return func(s *gosmbios.Structure) (any, error) {
+		v, err := parse(s)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[uint8]Decoder{
+		0:  dec(type0.Parse),
+		1:  dec(type1.Parse),
+		2:  dec(type2.Parse),
+		3:  dec(type3.Parse),
+		4:  dec(type4.Parse),
+		5:  dec(type5.Parse),
+		6:  dec(type6.Parse),
+		7:  dec(type7.Parse),
+		8:  dec(type8.Parse),
+		9:  dec(type9.Parse),
+		10: dec(type10.Parse),
+		11: dec(type11.Parse),
+		12: dec(type12.Parse),
+		13: dec(type13.Parse),
+		14: dec(type14.Parse),
+		15: dec(type15.Parse),
+		16: dec(type16.Parse),
+		17: dec(type17.Parse),
+		18: dec(type18.Parse),
+		19: dec(type19.Parse),
+		20: dec(type20.Parse),
+		21: dec(type21.Parse),
+		22: dec(type22.Parse),
+		23: dec(type23.Parse),
+		24: dec(type24.Parse),
+		25: dec(type25.Parse),
+		26: dec(type26.Parse),
+		27: dec(type27.Parse),
+		28: dec(type28.Parse),
+		29: dec(type29.Parse),
+		30: dec(type30.Parse),
+		31: dec(type31.Parse),
+		32: dec(type32.Parse),
+		33: dec(type33.Parse),
+		34: dec(type34.Parse),
+		35: dec(type35.Parse),
+		36: dec(type36.Parse),
+		37: dec(type37.Parse),
+		38: dec(type38.Parse),
+		39: dec(type39.Parse),
+		40: dec(type40.Parse),
+		41: dec(type41.Parse),
+		42: dec(type42.Parse),
+		43: dec(type43.Parse),
+		44: dec(type44.Parse),
+		45: dec(type45.Parse),
+		46: dec(type46.Parse),
+	}
+)
+
+// Register sets the decoder for a structure type, such as an OEM type,
+// replacing any previous decoder
+func Register(structType uint8, d Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[structType] = d
+}
+
+// Has reports whether a decoder is available for the structure type
+func Has(structType uint8) bool {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	_, ok := decoders[structType]
+	return ok
+}
+
+// Structure returns the typeN struct for a structure, such as
+// *type17.MemoryDevice for Type 17. It returns ErrNoDecoder for types without
+// a decoder, and the parser's error if decoding fails.
+func Structure(s *gosmbios.Structure) (any, error) {
+	decodersMu.RLock()
+	d, ok := decoders[s.Header.Type]
+	decodersMu.RUnlock()
+	if !ok {
+		return nil, ErrNoDecoder
+	}
+	return d(s)
+}
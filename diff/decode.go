@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/decode"
+)
+
+// Key fields identify a structure across tables when handles are not stable,
+// such as a DIMM by its locators or a processor by its socket
+var (
+	keyFieldsMu sync.RWMutex
+	keyFields   = map[uint8][]string{
+		4:  {"SocketDesignation"},
+		7:  {"SocketDesignation"},
+		8:  {"InternalReferenceDesignator", "ExternalReferenceDesignator"},
+		9:  {"Designation"},
+		17: {"DeviceLocator", "BankLocator"},
+		22: {"Location", "DeviceName"},
+		26: {"Description"},
+		27: {"Description"},
+		28: {"Description"},
+		29: {"Description"},
+		34: {"Description"},
+		35: {"Description"},
+		39: {"Location", "DeviceName"},
+		41: {"ReferenceDesignation"},
+		45: {"FirmwareComponentName"},
+	}
+)
+
+// RegisterKey sets the decoded struct fields that identify structures of the
+// given type across tables, replacing any previous key for the type
+func RegisterKey(structType uint8, fields ...string) {
+	keyFieldsMu.Lock()
+	defer keyFieldsMu.Unlock()
+	keyFields[structType] = append([]string(nil), fields...)
+}
+
+// keyOf returns the key field values of a decoded structure, or an empty
+// string if the type has no key or all key fields are empty
+func keyOf(structType uint8, decoded any) string {
+	keyFieldsMu.RLock()
+	names := keyFields[structType]
+	keyFieldsMu.RUnlock()
+
+	if decoded == nil || len(names) == 0 {
+		return ""
+	}
+	v := reflect.Indirect(reflect.ValueOf(decoded))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	var parts []string
+	for _, name := range names {
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			continue
+		}
+		if value := fmt.Sprint(f.Interface()); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// rawStructure is compared for types without a decoder, or when decoding fails
+type rawStructure struct {
+	Data    []byte
+	Strings []string
+}
+
+// decode returns the typeN struct for a structure, or its raw form
+func decodeStructure(s *gosmbios.Structure) any {
+	if v, err := decode.Structure(s); err == nil {
+		return v
+	}
+	var data []byte
+	if len(s.Data) > 4 {
+		data = s.Data[4:]
+	}
+	return &rawStructure{Data: data, Strings: s.Strings}
+}
+
+// field is one flattened value of a decoded structure
+type field struct {
+	path  string
+	value string
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	headerType   = reflect.TypeOf(gosmbios.Header{})
+)
+
+// flatten lists the leaf values of a decoded structure with dotted paths,
+// such as "Status" or "ContainedElements[1].Maximum". Values with a String
+// method are rendered with it; the structure header is skipped.
+func flatten(v any) []field {
+	var fields []field
+	var walk func(path string, v reflect.Value)
+	walk = func(path string, v reflect.Value) {
+		if !v.IsValid() {
+			return
+		}
+		if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			walk(path, v.Elem())
+			return
+		}
+		if path != "" && v.Type().Implements(stringerType) {
+			fields = append(fields, field{path, v.Interface().(fmt.Stringer).String()})
+			return
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			t := v.Type()
+			for i := 0; i < t.NumField(); i++ {
+				sf := t.Field(i)
+				if !sf.IsExported() || sf.Type == headerType {
+					continue
+				}
+				name := sf.Name
+				if path != "" {
+					name = path + "." + name
+				}
+				walk(name, v.Field(i))
+			}
+		case reflect.Slice, reflect.Array:
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				b := make([]byte, v.Len())
+				for i := range b {
+					b[i] = uint8(v.Index(i).Uint())
+				}
+				if len(b) > 0 {
+					fields = append(fields, field{path, hex.EncodeToString(b)})
+				}
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				walk(fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+			}
+		default:
+			fields = append(fields, field{path, fmt.Sprint(v.Interface())})
+		}
+	}
+	walk("", reflect.ValueOf(v))
+	return fields
+}
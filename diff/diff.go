@@ -0,0 +1,340 @@
+// Package diff compares two SMBIOS tables structure by structure, to answer
+// questions such as "what changed after the BIOS update or DIMM swap".
+//
+// Structures are matched by type plus a stable key: the socket, locator or
+// designation for types that have one (see RegisterKey), the type alone for
+// types that appear once in both tables, and the handle otherwise. Matched
+// structures are decoded with their typeN package and compared field by field.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types"
+)
+
+// Kind is the kind of a structure change
+type Kind string
+
+// Change kinds
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// FieldChange is a decoded field whose value differs. Before or After is
+// empty when the field only exists on one side.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Change describes an added, removed or changed structure
+type Change struct {
+	Kind         Kind          `json:"kind"`
+	Type         uint8         `json:"type"`
+	TypeName     string        `json:"type_name"`
+	Key          string        `json:"key,omitempty"`
+	HandleBefore string        `json:"handle_before,omitempty"`
+	HandleAfter  string        `json:"handle_after,omitempty"`
+	Fields       []FieldChange `json:"fields,omitempty"`
+}
+
+// Result is the outcome of comparing two tables. A version is empty when
+// the table had no entry point to read it from, such as a raw table dump,
+// and versions are then not compared.
+type Result struct {
+	VersionBefore string   `json:"version_before"`
+	VersionAfter  string   `json:"version_after"`
+	Changes       []Change `json:"changes"`
+}
+
+// Options controls what is compared
+type Options struct {
+	// IgnoreTypes lists structure types to leave out entirely
+	IgnoreTypes []uint8
+	// IgnoreFields lists field paths to leave out per type, e.g.
+	// {4: {"CurrentSpeed"}}. A path also ignores its sub-fields.
+	IgnoreFields map[uint8][]string
+}
+
+// ignored reports whether a field path is ignored for the type
+func (o Options) ignored(structType uint8, path string) bool {
+	for _, p := range o.IgnoreFields[structType] {
+		if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// HasChanges returns true if the tables differ
+func (r *Result) HasChanges() bool {
+	return len(r.Changes) > 0 || r.versionChanged()
+}
+
+// versionChanged reports whether both versions are known and differ
+func (r *Result) versionChanged() bool {
+	return r.VersionBefore != "" && r.VersionAfter != "" && r.VersionBefore != r.VersionAfter
+}
+
+// version returns the SMBIOS version of a table, or an empty string if its
+// entry point was synthesized
+func version(sm *gosmbios.SMBIOS) string {
+	if sm.EntryPoint.Synthesized {
+		return ""
+	}
+	return sm.EntryPoint.String()
+}
+
+// Counts returns the number of added, removed and changed structures
+func (r *Result) Counts() (added, removed, changed int) {
+	for _, c := range r.Changes {
+		switch c.Kind {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		case Changed:
+			changed++
+		}
+	}
+	return
+}
+
+// Compare compares two tables with default options
+func Compare(before, after *gosmbios.SMBIOS) *Result {
+	return CompareWith(before, after, Options{})
+}
+
+// entry is a structure prepared for matching
+type entry struct {
+	s       *gosmbios.Structure
+	decoded any
+	key     string
+}
+
+// CompareWith compares two tables. Changes are ordered by structure type,
+// then by their position in the tables.
+func CompareWith(before, after *gosmbios.SMBIOS, opts Options) *Result {
+	r := &Result{
+		VersionBefore: version(before),
+		VersionAfter:  version(after),
+	}
+
+	ignoredType := make(map[uint8]bool)
+	for _, t := range opts.IgnoreTypes {
+		ignoredType[t] = true
+	}
+	ignoredType[gosmbios.EndOfTableType] = true
+
+	a := group(before, ignoredType)
+	b := group(after, ignoredType)
+
+	typeSet := make(map[uint8]bool)
+	for t := range a {
+		typeSet[t] = true
+	}
+	for t := range b {
+		typeSet[t] = true
+	}
+	var typeList []uint8
+	for t := range typeSet {
+		typeList = append(typeList, t)
+	}
+	sort.Slice(typeList, func(i, j int) bool { return typeList[i] < typeList[j] })
+
+	for _, t := range typeList {
+		r.Changes = append(r.Changes, compareType(t, a[t], b[t], opts)...)
+	}
+	return r
+}
+
+// group decodes the structures of a table by type
+func group(sm *gosmbios.SMBIOS, ignored map[uint8]bool) map[uint8][]*entry {
+	groups := make(map[uint8][]*entry)
+	for i := range sm.Structures {
+		s := &sm.Structures[i]
+		if ignored[s.Header.Type] {
+			continue
+		}
+		groups[s.Header.Type] = append(groups[s.Header.Type], &entry{s: s, decoded: decodeStructure(s)})
+	}
+	return groups
+}
+
+// assignKeys sets the matching key of each entry. Keys repeated within a
+// table get an ordinal suffix, so the n-th "L1 Cache" matches the n-th.
+func assignKeys(structType uint8, entries []*entry, singleton bool) {
+	seen := make(map[string]int)
+	for _, e := range entries {
+		key := keyOf(structType, e.decoded)
+		if key == "" && !singleton {
+			key = fmt.Sprintf("handle 0x%04X", e.s.Header.Handle)
+		}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s #%d", key, n)
+		}
+		e.key = key
+	}
+}
+
+// compareType matches and compares the structures of one type
+func compareType(structType uint8, a, b []*entry, opts Options) []Change {
+	singleton := len(a) <= 1 && len(b) <= 1
+	assignKeys(structType, a, singleton)
+	assignKeys(structType, b, singleton)
+
+	byKey := make(map[string]*entry, len(b))
+	for _, e := range b {
+		byKey[e.key] = e
+	}
+	matched := make(map[*entry]bool)
+
+	name := types.TypeName(structType)
+	var changes []Change
+	for _, ea := range a {
+		eb, ok := byKey[ea.key]
+		if !ok {
+			changes = append(changes, Change{
+				Kind:         Removed,
+				Type:         structType,
+				TypeName:     name,
+				Key:          ea.key,
+				HandleBefore: handle(ea),
+				Fields:       fieldChanges(structType, flatten(ea.decoded), nil, opts),
+			})
+			continue
+		}
+		matched[eb] = true
+		fields := fieldChanges(structType, flatten(ea.decoded), flatten(eb.decoded), opts)
+		if len(fields) > 0 {
+			changes = append(changes, Change{
+				Kind:         Changed,
+				Type:         structType,
+				TypeName:     name,
+				Key:          ea.key,
+				HandleBefore: handle(ea),
+				HandleAfter:  handle(eb),
+				Fields:       fields,
+			})
+		}
+	}
+	for _, eb := range b {
+		if matched[eb] {
+			continue
+		}
+		changes = append(changes, Change{
+			Kind:        Added,
+			Type:        structType,
+			TypeName:    name,
+			Key:         eb.key,
+			HandleAfter: handle(eb),
+			Fields:      fieldChanges(structType, nil, flatten(eb.decoded), opts),
+		})
+	}
+	return changes
+}
+
+// handle formats an entry's handle
+func handle(e *entry) string {
+	return fmt.Sprintf("0x%04X", e.s.Header.Handle)
+}
+
+// fieldChanges compares flattened fields, in before order followed by
+// fields that only exist after
+func fieldChanges(structType uint8, before, after []field, opts Options) []FieldChange {
+	afterValues := make(map[string]string, len(after))
+	for _, f := range after {
+		afterValues[f.path] = f.value
+	}
+	beforePaths := make(map[string]bool, len(before))
+
+	var changes []FieldChange
+	for _, f := range before {
+		beforePaths[f.path] = true
+		if opts.ignored(structType, f.path) {
+			continue
+		}
+		if v, ok := afterValues[f.path]; !ok || v != f.value {
+			changes = append(changes, FieldChange{Field: f.path, Before: f.value, After: v})
+		}
+	}
+	for _, f := range after {
+		if beforePaths[f.path] || opts.ignored(structType, f.path) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: f.path, After: f.value})
+	}
+	return changes
+}
+
+// WriteText writes a human-readable report. Added and removed structures
+// list only their non-empty, non-zero fields.
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	if r.versionChanged() {
+		fmt.Fprintf(&b, "Version: %s -> %s\n\n", r.VersionBefore, r.VersionAfter)
+	}
+
+	for _, c := range r.Changes {
+		sign := map[Kind]string{Added: "+", Removed: "-", Changed: "~"}[c.Kind]
+		fmt.Fprintf(&b, "%s Type %d (%s)", sign, c.Type, c.TypeName)
+		if c.Key != "" {
+			fmt.Fprintf(&b, " [%s]", c.Key)
+		}
+		switch c.Kind {
+		case Added:
+			fmt.Fprintf(&b, " added, handle %s\n", c.HandleAfter)
+		case Removed:
+			fmt.Fprintf(&b, " removed, handle %s\n", c.HandleBefore)
+		default:
+			if c.HandleBefore != c.HandleAfter {
+				fmt.Fprintf(&b, " changed, handle %s -> %s\n", c.HandleBefore, c.HandleAfter)
+			} else {
+				fmt.Fprintf(&b, " changed, handle %s\n", c.HandleAfter)
+			}
+		}
+
+		// Added and removed structures list their set fields only
+		for _, f := range c.Fields {
+			switch c.Kind {
+			case Added:
+				if f.After != "" && f.After != "0" {
+					fmt.Fprintf(&b, "    %s: %s\n", f.Field, f.After)
+				}
+			case Removed:
+				if f.Before != "" && f.Before != "0" {
+					fmt.Fprintf(&b, "    %s: %s\n", f.Field, f.Before)
+				}
+			default:
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", f.Field, textValue(f.Before), textValue(f.After))
+			}
+		}
+	}
+
+	added, removed, changed := r.Counts()
+	if len(r.Changes) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d added, %d removed, %d changed\n", added, removed, changed)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// textValue quotes a value for text output, showing empty values explicitly
+func textValue(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", s)
+}
@@ -1,7 +1,6 @@
 package gosmbios
 
 import (
-	"bytes"
 	"encoding/binary"
 	"os"
 )
@@ -32,16 +31,20 @@ func readSMBIOSFromFile(filename string) (*SMBIOS, error) {
 	if err != nil {
 		return nil, err
 	}
+	return readSMBIOSFromBytes(data)
+}
 
+// readSMBIOSFromBytes parses the contents of a raw dump file
+func readSMBIOSFromBytes(data []byte) (*SMBIOS, error) {
 	// Check minimum size for header (9 + 1 + 1 + 1 + 1 + 1 + 1 + 4 + 8 = 28 bytes)
 	headerSize := 28
 	if len(data) < headerSize {
-		return nil, ErrInvalidStructure
+		return readRawTable(data)
 	}
 
-	// Check magic
+	// Check magic; files without it may be raw structure tables
 	if string(data[0:9]) != fileMagic {
-		return nil, ErrInvalidStructure
+		return readRawTable(data)
 	}
 
 	// Parse header manually for cross-platform compatibility
@@ -95,33 +98,50 @@ func readSMBIOSFromFile(filename string) (*SMBIOS, error) {
 	}, nil
 }
 
+// readRawTable parses a file holding only concatenated SMBIOS structures,
+// such as a QEMU "-smbios file=" blob or a copy of /sys/firmware/dmi/tables/DMI.
+// Since there is no entry point, an SMBIOS 3.0 entry point is synthesized.
+func readRawTable(data []byte) (*SMBIOS, error) {
+	structures, err := ParseStructures(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(structures) == 0 {
+		return nil, ErrInvalidStructure
+	}
+
+	// The structures must account for the whole file (apart from zero
+	// padding), otherwise this is not a structure table
+	consumed := 0
+	for i := range structures {
+		consumed += len(structures[i].Bytes())
+	}
+	if consumed > len(data) {
+		return nil, ErrInvalidStructure
+	}
+	for _, b := range data[consumed:] {
+		if b != 0 {
+			return nil, ErrInvalidStructure
+		}
+	}
+
+	return &SMBIOS{
+		EntryPoint: EntryPoint{
+			Type:         EntryPoint64Bit,
+			MajorVersion: 3,
+			MinorVersion: 0,
+			TableLength:  uint32(consumed),
+			Synthesized:  true,
+		},
+		Structures: structures,
+	}, nil
+}
+
 // writeSMBIOSToFile writes SMBIOS data to a raw dump file
 // The file contains a small header followed by the reconstructed raw SMBIOS table
 func writeSMBIOSToFile(sm *SMBIOS, filename string) error {
 	// First, reconstruct the raw table data exactly as it appears in memory
-	var tableData bytes.Buffer
-
-	for _, s := range sm.Structures {
-		// Write the raw formatted section (includes header)
-		tableData.Write(s.Data)
-
-		// Write string table
-		if len(s.Strings) == 0 {
-			// Empty string table: two null bytes
-			tableData.WriteByte(0)
-			tableData.WriteByte(0)
-		} else {
-			// Write each string followed by null terminator
-			for _, str := range s.Strings {
-				tableData.WriteString(str)
-				tableData.WriteByte(0)
-			}
-			// End of string table: additional null byte
-			tableData.WriteByte(0)
-		}
-	}
-
-	rawTable := tableData.Bytes()
+	rawTable := sm.TableBytes()
 
 	// Create output file
 	f, err := os.Create(filename)
@@ -0,0 +1,191 @@
+// Package fingerprint derives a stable machine identifier from SMBIOS identity fields.
+//
+// The identifier combines the Type 1 UUID and serial number, the Type 2
+// baseboard serial number and the Type 3 chassis serial number and asset tag.
+// Placeholder values (see gosmbios.IsPlaceholder) and unset or well-known
+// placeholder UUIDs are skipped, so hosts with cloned or blank UUIDs are still
+// told apart by their serial numbers.
+//
+// Algorithm (version 1): every usable input is normalized (trimmed, upper-case)
+// and written as "source=value\n" in the fixed order of DefaultWeights, after a
+// "gosmbios-fingerprint-v1\n" prefix. The ID is the first 16 bytes of the
+// SHA-256 of that text, hex encoded. Confidence is the sum of the weights of
+// the used inputs divided by the sum of all weights.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type3"
+)
+
+// Version is the fingerprint algorithm version. It changes whenever the
+// derivation changes in a way that produces different IDs.
+const Version = 1
+
+// ErrNoIdentity is returned when no usable identity field is present
+var ErrNoIdentity = errors.New("smbios: no usable identity fields for fingerprint")
+
+// Source identifies an SMBIOS field used as fingerprint input
+type Source string
+
+// Fingerprint input sources
+const (
+	SourceSystemUUID      Source = "system.uuid"
+	SourceSystemSerial    Source = "system.serial"
+	SourceBaseboardSerial Source = "baseboard.serial"
+	SourceChassisSerial   Source = "chassis.serial"
+	SourceChassisAssetTag Source = "chassis.asset_tag"
+)
+
+// Weight is the contribution of a source to the confidence score
+type Weight struct {
+	Source Source
+	Weight int
+}
+
+// DefaultWeights lists the sources in hashing order with their default weights.
+// The UUID is the strongest signal; asset tags are often set by hand and reused.
+var DefaultWeights = []Weight{
+	{SourceSystemUUID, 40},
+	{SourceSystemSerial, 25},
+	{SourceBaseboardSerial, 20},
+	{SourceChassisSerial, 10},
+	{SourceChassisAssetTag, 5},
+}
+
+// Input describes one candidate input and whether it was used
+type Input struct {
+	Source Source
+	Value  string // Normalized value (empty if the field is missing)
+	Weight int
+	Used   bool
+	Reason string // Why the input was skipped, empty if used
+}
+
+// Fingerprint is a derived machine identifier
+type Fingerprint struct {
+	ID         string  // 32 hex characters
+	Version    int     // Algorithm version used
+	Confidence float64 // 0.0 - 1.0, share of total weight backed by real values
+	Inputs     []Input // All candidate inputs, in hashing order
+}
+
+// Compute derives the fingerprint using DefaultWeights
+func Compute(sm *gosmbios.SMBIOS) (*Fingerprint, error) {
+	return ComputeWithWeights(sm, DefaultWeights)
+}
+
+// ComputeWithWeights derives the fingerprint using the given sources and weights.
+// Sources are hashed in the order given; a weight of 0 excludes the source
+// from the ID, for example to ignore UUIDs known to be cloned.
+func ComputeWithWeights(sm *gosmbios.SMBIOS, weights []Weight) (*Fingerprint, error) {
+	values := collect(sm)
+
+	var sb strings.Builder
+	sb.WriteString("gosmbios-fingerprint-v1\n")
+
+	fp := &Fingerprint{Version: Version}
+	total, used := 0, 0
+	for _, w := range weights {
+		in := Input{Source: w.Source, Weight: w.Weight}
+		total += w.Weight
+
+		v, ok := values[w.Source]
+		switch {
+		case w.Weight <= 0:
+			in.Value = v.value
+			in.Reason = "excluded"
+		case !ok || v.value == "":
+			in.Reason = "missing"
+		case v.reason != "":
+			in.Value = v.value
+			in.Reason = v.reason
+		default:
+			in.Value = v.value
+			in.Used = true
+			used += w.Weight
+			sb.WriteString(string(w.Source))
+			sb.WriteByte('=')
+			sb.WriteString(v.value)
+			sb.WriteByte('\n')
+		}
+		fp.Inputs = append(fp.Inputs, in)
+	}
+
+	if used == 0 {
+		return fp, ErrNoIdentity
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	fp.ID = hex.EncodeToString(sum[:16])
+	fp.Confidence = float64(used) / float64(total)
+	return fp, nil
+}
+
+// UsedSources returns the sources that contributed to the ID
+func (f *Fingerprint) UsedSources() []Source {
+	var result []Source
+	for _, in := range f.Inputs {
+		if in.Used {
+			result = append(result, in.Source)
+		}
+	}
+	return result
+}
+
+// candidate is a normalized field value and the reason it is unusable, if any
+type candidate struct {
+	value  string
+	reason string
+}
+
+// normalize trims and upper-cases a value for stable hashing
+func normalize(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// stringCandidate classifies a string field
+func stringCandidate(s string) candidate {
+	c := candidate{value: normalize(s)}
+	if c.value != "" && !gosmbios.IsMeaningful(s) {
+		c.reason = "placeholder"
+	}
+	return c
+}
+
+// collect reads all candidate values from the table
+func collect(sm *gosmbios.SMBIOS) map[Source]candidate {
+	values := make(map[Source]candidate)
+
+	if sys, err := type1.Get(sm); err == nil {
+		uuid := candidate{value: sys.UUID.String()}
+		switch {
+		case sys.UUID.IsZero():
+			uuid.reason = "not set"
+		case sys.UUID.IsInvalid():
+			uuid.reason = "not settable"
+		case sys.UUID.IsPlaceholder():
+			uuid.reason = "placeholder"
+		}
+		values[SourceSystemUUID] = uuid
+		values[SourceSystemSerial] = stringCandidate(sys.SerialNumber)
+	}
+
+	if board, err := type2.Get(sm); err == nil {
+		values[SourceBaseboardSerial] = stringCandidate(board.SerialNumber)
+	}
+
+	if chassis, err := type3.Get(sm); err == nil {
+		values[SourceChassisSerial] = stringCandidate(chassis.SerialNumber)
+		values[SourceChassisAssetTag] = stringCandidate(chassis.AssetTag)
+	}
+
+	return values
+}
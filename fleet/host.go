@@ -0,0 +1,96 @@
+// Package fleet aggregates SMBIOS dumps from many hosts, such as the
+// <UUID>.smbios files written by "smbiosdump -f bin", into fleet reports:
+// counts by manufacturer, model, BIOS version and CPU model, memory per host,
+// DIMM part-number histograms and TPM presence.
+package fleet
+
+import (
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type17"
+	"github.com/earentir/gosmbios/types/type4"
+	"github.com/earentir/gosmbios/types/type43"
+)
+
+// DIMM is a populated memory device
+type DIMM struct {
+	Locator      string `json:"locator"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	PartNumber   string `json:"part_number,omitempty"`
+	SizeMB       uint64 `json:"size_mb"`
+	Speed        uint32 `json:"speed_mts,omitempty"`
+}
+
+// Host is the summary of one dump
+type Host struct {
+	Source        string `json:"source"`
+	UUID          string `json:"uuid,omitempty"`
+	Manufacturer  string `json:"manufacturer,omitempty"`
+	Model         string `json:"model,omitempty"`
+	SerialNumber  string `json:"serial_number,omitempty"`
+	BIOSVendor    string `json:"bios_vendor,omitempty"`
+	BIOSVersion   string `json:"bios_version,omitempty"`
+	BIOSDate      string `json:"bios_date,omitempty"`
+	CPUModel      string `json:"cpu_model,omitempty"`
+	Sockets       int    `json:"sockets"`
+	TotalMemoryMB uint64 `json:"total_memory_mb"`
+	DIMMs         []DIMM `json:"dimms,omitempty"`
+	TPM           bool   `json:"tpm"`
+	TPMFamily     string `json:"tpm_family,omitempty"`
+}
+
+// Summarize extracts the fleet-relevant fields of a table. Source names the
+// dump the table was read from.
+func Summarize(sm *gosmbios.SMBIOS, source string) *Host {
+	h := &Host{Source: source}
+
+	if sys, err := type1.Get(sm); err == nil {
+		if sys.UUID.IsMeaningful() {
+			h.UUID = sys.UUID.String()
+		}
+		h.Manufacturer = strings.TrimSpace(sys.Manufacturer)
+		h.Model = strings.TrimSpace(sys.ProductName)
+		h.SerialNumber = strings.TrimSpace(gosmbios.Sanitize(sys.SerialNumber))
+	}
+
+	if bios, err := type0.Get(sm); err == nil {
+		h.BIOSVendor = strings.TrimSpace(bios.Vendor)
+		h.BIOSVersion = strings.TrimSpace(bios.Version)
+		h.BIOSDate = strings.TrimSpace(bios.ReleaseDate)
+	}
+
+	if procs, err := type4.GetAll(sm); err == nil {
+		for _, p := range procs {
+			if !p.Status.IsPopulated() {
+				continue
+			}
+			h.Sockets++
+			if h.CPUModel == "" {
+				h.CPUModel = strings.TrimSpace(p.ProcessorVersion)
+			}
+		}
+	}
+
+	if mems, err := type17.GetPopulated(sm); err == nil {
+		for _, m := range mems {
+			h.TotalMemoryMB += m.Size
+			h.DIMMs = append(h.DIMMs, DIMM{
+				Locator:      m.DeviceLocator,
+				Manufacturer: strings.TrimSpace(m.Manufacturer),
+				PartNumber:   strings.TrimSpace(m.PartNumber),
+				SizeMB:       m.Size,
+				Speed:        m.GetSpeed(),
+			})
+		}
+	}
+
+	if tpm, err := type43.Get(sm); err == nil && tpm.IsSupported() {
+		h.TPM = true
+		h.TPMFamily = tpm.Family()
+	}
+
+	return h
+}
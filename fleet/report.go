@@ -0,0 +1,231 @@
+package fleet
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report is the result of a scan
+type Report struct {
+	Hosts  []*Host
+	Errors []*FileError
+}
+
+// Count is one histogram bucket
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Counts holds the fleet histograms, each sorted by descending count
+type Counts struct {
+	Hosts           int     `json:"hosts"`
+	Errors          int     `json:"errors"`
+	Manufacturers   []Count `json:"manufacturers"`
+	Models          []Count `json:"models"`
+	BIOSVersions    []Count `json:"bios_versions"`
+	CPUModels       []Count `json:"cpu_models"`
+	TotalMemory     []Count `json:"total_memory"`
+	DIMMPartNumbers []Count `json:"dimm_part_numbers"`
+	TPM             []Count `json:"tpm"`
+}
+
+// unknown is the histogram bucket for empty values
+const unknown = "(unknown)"
+
+// join combines non-empty values with a space, or returns unknown
+func join(values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return unknown
+	}
+	return strings.Join(parts, " ")
+}
+
+// sizeString formats a memory size in MB
+func sizeString(mb uint64) string {
+	switch {
+	case mb == 0:
+		return unknown
+	case mb%(1024*1024) == 0:
+		return fmt.Sprintf("%d TB", mb/(1024*1024))
+	case mb%1024 == 0:
+		return fmt.Sprintf("%d GB", mb/1024)
+	default:
+		return fmt.Sprintf("%d MB", mb)
+	}
+}
+
+// sorted converts a histogram map to buckets by descending count, then value
+func sorted(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for v, n := range m {
+		counts = append(counts, Count{Value: v, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+// Counts computes the fleet histograms. Models and BIOS versions include
+// the manufacturer or vendor, since they are only unique per vendor. DIMM
+// part numbers count modules, not hosts.
+func (r *Report) Counts() *Counts {
+	manufacturers := make(map[string]int)
+	models := make(map[string]int)
+	biosVersions := make(map[string]int)
+	cpuModels := make(map[string]int)
+	totalMemory := make(map[string]int)
+	partNumbers := make(map[string]int)
+	tpm := make(map[string]int)
+
+	for _, h := range r.Hosts {
+		manufacturers[join(h.Manufacturer)]++
+		models[join(h.Manufacturer, h.Model)]++
+		biosVersions[join(h.BIOSVendor, h.BIOSVersion)]++
+		cpuModels[join(h.CPUModel)]++
+		totalMemory[sizeString(h.TotalMemoryMB)]++
+		for _, d := range h.DIMMs {
+			partNumbers[join(d.PartNumber)]++
+		}
+		if h.TPM {
+			tpm[h.TPMFamily]++
+		} else {
+			tpm["None"]++
+		}
+	}
+
+	return &Counts{
+		Hosts:           len(r.Hosts),
+		Errors:          len(r.Errors),
+		Manufacturers:   sorted(manufacturers),
+		Models:          sorted(models),
+		BIOSVersions:    sorted(biosVersions),
+		CPUModels:       sorted(cpuModels),
+		TotalMemory:     sorted(totalMemory),
+		DIMMPartNumbers: sorted(partNumbers),
+		TPM:             sorted(tpm),
+	}
+}
+
+// WriteHostsCSV writes one row per host. DIMM part numbers are joined with
+// ';' in locator order.
+func (r *Report) WriteHostsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"source", "uuid", "manufacturer", "model", "serial_number",
+		"bios_vendor", "bios_version", "bios_date", "cpu_model", "sockets",
+		"total_memory_mb", "dimm_count", "dimm_part_numbers", "tpm", "tpm_family",
+	})
+	for _, h := range r.Hosts {
+		var parts []string
+		for _, d := range h.DIMMs {
+			parts = append(parts, d.PartNumber)
+		}
+		cw.Write([]string{
+			h.Source, h.UUID, h.Manufacturer, h.Model, h.SerialNumber,
+			h.BIOSVendor, h.BIOSVersion, h.BIOSDate, h.CPUModel, strconv.Itoa(h.Sockets),
+			strconv.FormatUint(h.TotalMemoryMB, 10), strconv.Itoa(len(h.DIMMs)),
+			strings.Join(parts, ";"), strconv.FormatBool(h.TPM), h.TPMFamily,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCountsCSV writes the histograms as category,value,count rows
+func (r *Report) WriteCountsCSV(w io.Writer) error {
+	c := r.Counts()
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"category", "value", "count"})
+	for _, cat := range c.categories() {
+		for _, b := range cat.counts {
+			cw.Write([]string{cat.key, b.Value, strconv.Itoa(b.Count)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// category is a named histogram, for output
+type category struct {
+	key    string
+	title  string
+	counts []Count
+}
+
+// categories lists the histograms in output order
+func (c *Counts) categories() []category {
+	return []category{
+		{"manufacturer", "Manufacturers", c.Manufacturers},
+		{"model", "Models", c.Models},
+		{"bios_version", "BIOS Versions", c.BIOSVersions},
+		{"cpu_model", "CPU Models", c.CPUModels},
+		{"total_memory", "Total Memory per Host", c.TotalMemory},
+		{"dimm_part_number", "DIMM Part Numbers", c.DIMMPartNumbers},
+		{"tpm", "TPM", c.TPM},
+	}
+}
+
+// jsonError is the JSON form of a FileError
+type jsonError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// WriteJSON writes the hosts, histograms and errors as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := struct {
+		Summary *Counts     `json:"summary"`
+		Hosts   []*Host     `json:"hosts"`
+		Errors  []jsonError `json:"errors,omitempty"`
+	}{
+		Summary: r.Counts(),
+		Hosts:   r.Hosts,
+	}
+	for _, e := range r.Errors {
+		out.Errors = append(out.Errors, jsonError{Path: e.Path, Error: e.Err.Error()})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// WriteText writes a human-readable summary, listing at most limit entries
+// per histogram (0 for all)
+func (r *Report) WriteText(w io.Writer, limit int) error {
+	c := r.Counts()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Hosts:  %d\n", c.Hosts)
+	fmt.Fprintf(&b, "Errors: %d\n", c.Errors)
+
+	for _, cat := range c.categories() {
+		fmt.Fprintf(&b, "\n%s:\n", cat.title)
+		for i, bucket := range cat.counts {
+			if limit > 0 && i == limit {
+				fmt.Fprintf(&b, "  ... %d more\n", len(cat.counts)-limit)
+				break
+			}
+			fmt.Fprintf(&b, "  %6d  %s\n", bucket.Count, bucket.Value)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
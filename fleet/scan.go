@@ -0,0 +1,279 @@
+package fleet
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/earentir/gosmbios"
+)
+
+// MaxDumpSize is the largest dump accepted from an archive. SMBIOS tables are
+// limited to a few megabytes, so anything larger is not a dump.
+const MaxDumpSize = 16 << 20
+
+// ErrDumpTooLarge is returned for archive members larger than MaxDumpSize
+var ErrDumpTooLarge = errors.New("smbios: dump file too large")
+
+// FileError is a dump that could not be read or parsed
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface
+func (e *FileError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Options controls a scan
+type Options struct {
+	// Workers is the number of dumps parsed in parallel (default: number of CPUs)
+	Workers int
+	// Extensions lists the file extensions treated as dumps, case-insensitively
+	// (default: ".smbios")
+	Extensions []string
+	// OnError is called for each dump that fails, as it happens. The scan
+	// continues either way; failures are also listed in Report.Errors.
+	OnError func(err *FileError)
+}
+
+// job is one dump to parse
+type job struct {
+	source string
+	read   func() ([]byte, error)
+}
+
+// isArchive reports whether a path names a supported archive
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan reads every dump found in the given paths and summarizes them. A path
+// may be a directory (walked recursively), a zip, tar or gzipped tar archive,
+// or a single dump file. Errors for individual dumps (including truncated
+// archives) do not stop the scan; an error is only returned for paths that
+// cannot be opened, after the remaining paths have been scanned.
+func Scan(paths []string, opts Options) (*Report, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	extensions := opts.Extensions
+	if len(extensions) == 0 {
+		extensions = []string{".smbios"}
+	}
+	isDump := func(name string) bool {
+		lower := strings.ToLower(name)
+		for _, ext := range extensions {
+			if strings.HasSuffix(lower, strings.ToLower(ext)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	report := &Report{}
+	var mu sync.Mutex
+	fail := func(source string, err error) {
+		fe := &FileError{Path: source, Err: err}
+		mu.Lock()
+		report.Errors = append(report.Errors, fe)
+		mu.Unlock()
+		if opts.OnError != nil {
+			opts.OnError(fe)
+		}
+	}
+
+	// process reads and summarizes one dump. A panic while decoding a
+	// corrupt dump is reported as that dump's error rather than ending the
+	// scan.
+	process := func(j job) {
+		defer func() {
+			if r := recover(); r != nil {
+				fail(j.source, fmt.Errorf("smbios: panic decoding dump: %v", r))
+			}
+		}()
+		data, err := j.read()
+		if err != nil {
+			fail(j.source, err)
+			return
+		}
+		sm, err := gosmbios.ReadFromBytes(data)
+		if err != nil {
+			fail(j.source, err)
+			return
+		}
+		host := Summarize(sm, j.source)
+		mu.Lock()
+		report.Hosts = append(report.Hosts, host)
+		mu.Unlock()
+	}
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				process(j)
+			}
+		}()
+	}
+
+	var errs []error
+	var closers []io.Closer
+	for _, path := range paths {
+		var err error
+		closers, err = produce(path, isDump, jobs, fail, closers)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for _, c := range closers {
+		c.Close()
+	}
+
+	sort.Slice(report.Hosts, func(i, j int) bool { return report.Hosts[i].Source < report.Hosts[j].Source })
+	sort.Slice(report.Errors, func(i, j int) bool { return report.Errors[i].Path < report.Errors[j].Path })
+	return report, errors.Join(errs...)
+}
+
+// produce queues the dumps found at path. Archives that must stay open until
+// the workers finish are appended to closers.
+func produce(path string, isDump func(string) bool, jobs chan<- job, fail func(string, error), closers []io.Closer) ([]io.Closer, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return closers, err
+	}
+
+	lower := strings.ToLower(path)
+	switch {
+	case info.IsDir():
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == path {
+					return err
+				}
+				fail(p, err)
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() && isDump(p) {
+				file := p
+				jobs <- job{source: file, read: func() ([]byte, error) { return os.ReadFile(file) }}
+			}
+			return nil
+		})
+		return closers, err
+
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return closers, err
+		}
+		closers = append(closers, zr)
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !isDump(f.Name) {
+				continue
+			}
+			member := f
+			jobs <- job{source: path + ":" + f.Name, read: func() ([]byte, error) {
+				if member.UncompressedSize64 > MaxDumpSize {
+					return nil, ErrDumpTooLarge
+				}
+				rc, err := member.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return readLimited(rc)
+			}}
+		}
+		return closers, nil
+
+	case isArchive(path):
+		f, err := os.Open(path)
+		if err != nil {
+			return closers, err
+		}
+		defer f.Close()
+
+		var r io.Reader = f
+		if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return closers, err
+			}
+			defer gz.Close()
+			r = gz
+		}
+
+		// Tar members can only be read in order, so they are read here and
+		// parsed by the workers
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return closers, nil
+			}
+			if err != nil {
+				// Dumps before the damage have been queued already
+				fail(path, err)
+				return closers, nil
+			}
+			if hdr.Typeflag != tar.TypeReg || !isDump(hdr.Name) {
+				continue
+			}
+			source := path + ":" + hdr.Name
+			data, err := readLimited(tr)
+			if err != nil {
+				fail(source, err)
+				continue
+			}
+			jobs <- job{source: source, read: func() ([]byte, error) { return data, nil }}
+		}
+
+	default:
+		jobs <- job{source: path, read: func() ([]byte, error) { return os.ReadFile(path) }}
+		return closers, nil
+	}
+}
+
+// readLimited reads a dump, rejecting anything larger than MaxDumpSize
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxDumpSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDumpSize {
+		return nil, ErrDumpTooLarge
+	}
+	return data, nil
+}
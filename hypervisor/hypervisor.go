@@ -0,0 +1,348 @@
+// Package hypervisor detects virtual machines and identifies the hypervisor
+// from SMBIOS data.
+//
+// Detection matches a table of signatures against vendor and product strings
+// in Types 0, 1, 2, 3 and 11, the Type 4 processor strings and CPUID signature,
+// and the Type 0 "virtual machine" characteristic. Each match is weighted; the
+// hypervisor with the highest total wins. The table can be extended with
+// RegisterSignature.
+package hypervisor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type11"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type3"
+	"github.com/earentir/gosmbios/types/type4"
+)
+
+// Hypervisor identifies a virtualization platform
+type Hypervisor string
+
+// Known hypervisors
+const (
+	None        Hypervisor = ""
+	Unknown     Hypervisor = "Unknown"
+	KVM         Hypervisor = "KVM/QEMU"
+	VMware      Hypervisor = "VMware"
+	HyperV      Hypervisor = "Hyper-V"
+	Xen         Hypervisor = "Xen"
+	VirtualBox  Hypervisor = "VirtualBox"
+	Parallels   Hypervisor = "Parallels"
+	Bhyve       Hypervisor = "bhyve"
+	Firecracker Hypervisor = "Firecracker"
+)
+
+// Field names an SMBIOS value that signatures are matched against
+type Field string
+
+// Matchable fields
+const (
+	FieldBIOSVendor            Field = "bios.vendor"
+	FieldBIOSVersion           Field = "bios.version"
+	FieldBIOSCharacteristics   Field = "bios.characteristics"
+	FieldSystemManufacturer    Field = "system.manufacturer"
+	FieldSystemProduct         Field = "system.product"
+	FieldSystemVersion         Field = "system.version"
+	FieldSystemSerial          Field = "system.serial"
+	FieldSystemFamily          Field = "system.family"
+	FieldBoardManufacturer     Field = "baseboard.manufacturer"
+	FieldBoardProduct          Field = "baseboard.product"
+	FieldChassisManufacturer   Field = "chassis.manufacturer"
+	FieldOEMString             Field = "oem.string"
+	FieldProcessorManufacturer Field = "processor.manufacturer"
+	FieldProcessorVersion      Field = "processor.version"
+	FieldProcessorSignature    Field = "processor.signature" // CPUID leaf 1 EAX as 8 hex digits
+)
+
+// Signature is a pattern that, when it matches a field, counts as evidence
+// for a hypervisor
+type Signature struct {
+	Hypervisor Hypervisor
+	Field      Field
+	Pattern    *regexp.Regexp
+	// Weight is added to the hypervisor's score on a match. A signature with
+	// weight 0 is only used to extract the version once the hypervisor is known.
+	Weight int
+	// If Pattern has a capture group, its first submatch is the version,
+	// prefixed with VersionPrefix
+	VersionPrefix string
+}
+
+// Evidence is a single signature match
+type Evidence struct {
+	Hypervisor Hypervisor
+	Field      Field
+	Value      string
+	Weight     int
+}
+
+// String returns a human-readable evidence description
+func (e Evidence) String() string {
+	return fmt.Sprintf("%s=%q (%s, +%d)", e.Field, e.Value, e.Hypervisor, e.Weight)
+}
+
+// Result is the outcome of hypervisor detection
+type Result struct {
+	IsVirtual  bool
+	Hypervisor Hypervisor // None on bare metal, Unknown if virtual but unidentified
+	Version    string     // Version encoded in SMBIOS, if any
+	Score      int        // Total weight of evidence for Hypervisor
+	Evidence   []Evidence // All matches in table order, including ones below MinScore
+}
+
+// sig builds a case-insensitive signature
+func sig(h Hypervisor, f Field, pattern string, weight int) Signature {
+	return Signature{Hypervisor: h, Field: f, Pattern: regexp.MustCompile("(?i)" + pattern), Weight: weight}
+}
+
+// versionSig builds a case-insensitive signature that extracts a version
+func versionSig(h Hypervisor, f Field, pattern string, weight int, prefix string) Signature {
+	s := sig(h, f, pattern, weight)
+	s.VersionPrefix = prefix
+	return s
+}
+
+var (
+	signaturesMu sync.RWMutex
+	signatures   = []Signature{
+		// KVM / QEMU (including KVM-based clouds)
+		sig(KVM, FieldSystemManufacturer, `^QEMU$`, 40),
+		sig(KVM, FieldSystemProduct, `^Standard PC \(`, 30),
+		sig(KVM, FieldSystemProduct, `^KVM$`, 40),
+		sig(KVM, FieldSystemProduct, `^(?:RHEL|RHEV|oVirt Node)`, 30),
+		sig(KVM, FieldSystemProduct, `^OpenStack (?:Nova|Compute)$`, 30),
+		sig(KVM, FieldSystemProduct, `^Google Compute Engine$`, 20),
+		sig(KVM, FieldBIOSVendor, `^Google$`, 10),
+		sig(KVM, FieldSystemManufacturer, `^Amazon EC2$`, 20),
+		sig(KVM, FieldBIOSVendor, `^SeaBIOS$`, 20),
+		sig(KVM, FieldChassisManufacturer, `^QEMU$`, 30),
+		sig(KVM, FieldProcessorManufacturer, `^QEMU$`, 30),
+		sig(KVM, FieldProcessorVersion, `^QEMU Virtual CPU`, 30),
+		versionSig(KVM, FieldSystemVersion, `^pc-(?:i440fx|q35)-(\d+\.\d+)`, 30, "machine "),
+		versionSig(KVM, FieldSystemVersion, `^virt-(\d+\.\d+)`, 30, "machine "),
+		versionSig(KVM, FieldProcessorVersion, `^pc-(?:i440fx|q35)-(\d+\.\d+)`, 30, "machine "),
+		sig(KVM, FieldProcessorSignature, `^00000F61$`, 5), // kvm64 CPU model
+		sig(KVM, FieldProcessorSignature, `^00000663$`, 5), // qemu32 CPU model
+
+		// VMware
+		sig(VMware, FieldSystemManufacturer, `^VMware, Inc\.?$`, 40),
+		versionSig(VMware, FieldSystemProduct, `^(VMware\d+,1)$`, 40, ""),
+		sig(VMware, FieldSystemProduct, `^VMware Virtual Platform$`, 40),
+		sig(VMware, FieldSystemSerial, `^VMware-`, 30),
+		sig(VMware, FieldBIOSVersion, `^VMW`, 20),
+		sig(VMware, FieldBoardProduct, `^440BX Desktop Reference Platform$`, 10),
+		sig(VMware, FieldOEMString, `^Welcome to the Virtual Machine$`, 20),
+
+		// Microsoft Hyper-V
+		sig(HyperV, FieldSystemManufacturer, `^Microsoft Corporation$`, 10),
+		sig(HyperV, FieldSystemProduct, `^Virtual Machine$`, 30),
+		sig(HyperV, FieldSystemFamily, `^Virtual Machine$`, 10),
+		sig(HyperV, FieldBoardProduct, `^Virtual Machine$`, 20),
+		sig(HyperV, FieldBIOSVersion, `Hyper-V UEFI Release`, 40),
+		sig(HyperV, FieldOEMString, `^\[MS_VM_CERT/`, 40),
+		versionSig(HyperV, FieldSystemVersion, `^(\d+\.\d+)$`, 0, "config "),
+
+		// Xen
+		sig(Xen, FieldBIOSVendor, `^Xen$`, 40),
+		sig(Xen, FieldSystemManufacturer, `^Xen$`, 40),
+		sig(Xen, FieldSystemProduct, `^HVM domU$`, 40),
+		versionSig(Xen, FieldBIOSVersion, `^(\d+\.\d+(?:\.\d+)?)`, 0, ""),
+
+		// Oracle VirtualBox
+		sig(VirtualBox, FieldBIOSVendor, `^innotek GmbH$`, 40),
+		sig(VirtualBox, FieldBIOSVersion, `^VirtualBox$`, 30),
+		sig(VirtualBox, FieldSystemProduct, `^VirtualBox$`, 40),
+		sig(VirtualBox, FieldBoardProduct, `^VirtualBox$`, 20),
+		versionSig(VirtualBox, FieldOEMString, `^vboxVer_([\d.]+)$`, 30, ""),
+		sig(VirtualBox, FieldOEMString, `^vboxRev_`, 10),
+
+		// Parallels
+		sig(Parallels, FieldSystemManufacturer, `^Parallels`, 40),
+		sig(Parallels, FieldSystemProduct, `^Parallels`, 40),
+		sig(Parallels, FieldBIOSVendor, `^Parallels`, 30),
+		versionSig(Parallels, FieldBIOSVersion, `^(\d+\.\d+\.\d+)`, 0, ""),
+
+		// FreeBSD bhyve
+		sig(Bhyve, FieldBIOSVendor, `^BHYVE$`, 40),
+		sig(Bhyve, FieldSystemProduct, `^BHYVE$`, 40),
+		sig(Bhyve, FieldSystemManufacturer, `^FreeBSD$`, 20),
+		versionSig(Bhyve, FieldBIOSVersion, `^(\d+\.\d+)`, 0, ""),
+
+		// Firecracker microVMs
+		sig(Firecracker, FieldSystemManufacturer, `Firecracker`, 40),
+		sig(Firecracker, FieldSystemProduct, `Firecracker`, 40),
+		sig(Firecracker, FieldBIOSVendor, `Firecracker`, 40),
+	}
+)
+
+// MinScore is the score a hypervisor needs before the system is reported as
+// virtual. Single weak matches, such as a "Microsoft Corporation" manufacturer
+// on a Surface laptop, stay below it.
+const MinScore = 30
+
+// vmCharacteristicWeight is the weight of the Type 0 "virtual machine" bit.
+// It proves virtualization but does not identify the hypervisor.
+const vmCharacteristicWeight = 30
+
+// RegisterSignature adds a signature to the detection table
+func RegisterSignature(s Signature) {
+	signaturesMu.Lock()
+	defer signaturesMu.Unlock()
+	signatures = append(signatures, s)
+}
+
+// Signatures returns a copy of the current detection table
+func Signatures() []Signature {
+	signaturesMu.RLock()
+	defer signaturesMu.RUnlock()
+	result := make([]Signature, len(signatures))
+	copy(result, signatures)
+	return result
+}
+
+// fieldValue is a single value read from the table
+type fieldValue struct {
+	field Field
+	value string
+}
+
+// collect reads all matchable field values from the table
+func collect(sm *gosmbios.SMBIOS) []fieldValue {
+	var values []fieldValue
+	add := func(f Field, v string) {
+		if v != "" {
+			values = append(values, fieldValue{f, v})
+		}
+	}
+
+	if bios, err := type0.Get(sm); err == nil {
+		add(FieldBIOSVendor, bios.Vendor)
+		add(FieldBIOSVersion, bios.Version)
+	}
+	if sys, err := type1.Get(sm); err == nil {
+		add(FieldSystemManufacturer, sys.Manufacturer)
+		add(FieldSystemProduct, sys.ProductName)
+		add(FieldSystemVersion, sys.Version)
+		add(FieldSystemSerial, sys.SerialNumber)
+		add(FieldSystemFamily, sys.Family)
+	}
+	if board, err := type2.Get(sm); err == nil {
+		add(FieldBoardManufacturer, board.Manufacturer)
+		add(FieldBoardProduct, board.Product)
+	}
+	if chassis, err := type3.Get(sm); err == nil {
+		add(FieldChassisManufacturer, chassis.Manufacturer)
+	}
+	if oems, err := type11.GetAll(sm); err == nil {
+		for _, oem := range oems {
+			for _, s := range oem.Strings {
+				add(FieldOEMString, s)
+			}
+		}
+	}
+	if procs, err := type4.GetAll(sm); err == nil {
+		for _, p := range procs {
+			add(FieldProcessorManufacturer, p.ProcessorManufacturer)
+			add(FieldProcessorVersion, p.ProcessorVersion)
+			if p.ProcessorID != 0 {
+				add(FieldProcessorSignature, fmt.Sprintf("%08X", uint32(p.ProcessorID)))
+			}
+		}
+	}
+
+	return values
+}
+
+// Detect identifies the hypervisor using the registered signatures
+func Detect(sm *gosmbios.SMBIOS) *Result {
+	return DetectWith(sm, Signatures())
+}
+
+// DetectWith identifies the hypervisor using the given signatures only
+func DetectWith(sm *gosmbios.SMBIOS, sigs []Signature) *Result {
+	result := &Result{}
+	values := collect(sm)
+
+	scores := make(map[Hypervisor]int)
+	for _, s := range sigs {
+		if s.Weight <= 0 {
+			continue
+		}
+		for _, v := range values {
+			if v.field == s.Field && s.Pattern.MatchString(v.value) {
+				scores[s.Hypervisor] += s.Weight
+				result.Evidence = append(result.Evidence, Evidence{
+					Hypervisor: s.Hypervisor,
+					Field:      s.Field,
+					Value:      v.value,
+					Weight:     s.Weight,
+				})
+			}
+		}
+	}
+
+	if bios, err := type0.Get(sm); err == nil && bios.IsVirtualMachine() {
+		result.Evidence = append(result.Evidence, Evidence{
+			Hypervisor: Unknown,
+			Field:      FieldBIOSCharacteristics,
+			Value:      "Virtual Machine",
+			Weight:     vmCharacteristicWeight,
+		})
+		scores[Unknown] += vmCharacteristicWeight
+	}
+
+	// Pick the identified hypervisor with the highest score; ties are broken
+	// by name so the result is deterministic
+	var candidates []Hypervisor
+	for h, score := range scores {
+		if h != Unknown && score >= MinScore {
+			candidates = append(candidates, h)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if scores[candidates[i]] != scores[candidates[j]] {
+			return scores[candidates[i]] > scores[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	if len(candidates) == 0 {
+		if scores[Unknown] >= MinScore {
+			result.IsVirtual = true
+			result.Hypervisor = Unknown
+			result.Score = scores[Unknown]
+		}
+		return result
+	}
+	result.IsVirtual = true
+	result.Hypervisor = candidates[0]
+	result.Score = scores[result.Hypervisor] + scores[Unknown]
+	result.Version = extractVersion(result.Hypervisor, values, sigs)
+	return result
+}
+
+// extractVersion returns the first version captured by a signature of h
+func extractVersion(h Hypervisor, values []fieldValue, sigs []Signature) string {
+	for _, s := range sigs {
+		if s.Hypervisor != h || s.Pattern.NumSubexp() == 0 {
+			continue
+		}
+		for _, v := range values {
+			if v.field != s.Field {
+				continue
+			}
+			if m := s.Pattern.FindStringSubmatch(v.value); len(m) > 1 && m[1] != "" {
+				return s.VersionPrefix + m[1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package gosmbios
+
+// Structure type values with special meaning in the structure table
+const (
+	// InactiveType marks a structure the BIOS has disabled by rewriting its type.
+	// The original type is not preserved in the table.
+	InactiveType uint8 = 126
+	// EndOfTableType marks the last structure in the table
+	EndOfTableType uint8 = 127
+	// OEMTypeMin is the first structure type reserved for OEM-specific use
+	OEMTypeMin uint8 = 128
+)
+
+// specLengths lists the formatted-section lengths defined by DSP0134 for each
+// specification revision of a structure type. It is used to infer which type
+// an inactive structure most likely had before the BIOS rewrote it.
+// Only types with a fixed set of lengths are listed.
+var specLengths = map[uint8][]uint8{
+	0:  {0x12, 0x13, 0x14, 0x18, 0x1A},
+	1:  {0x08, 0x19, 0x1B},
+	4:  {0x1A, 0x20, 0x23, 0x28, 0x2A, 0x30, 0x32},
+	7:  {0x0F, 0x13, 0x1B},
+	16: {0x0F, 0x17},
+	17: {0x15, 0x1B, 0x1C, 0x22, 0x28, 0x54, 0x5C, 0x64},
+	18: {0x17},
+	19: {0x0F, 0x1F},
+	20: {0x13, 0x23},
+	32: {0x14},
+	33: {0x1F},
+	43: {0x1F},
+}
+
+// StructureOptions controls which structures are returned by Select
+type StructureOptions struct {
+	IncludeInactive   bool // Include Type 126 (Inactive) structures
+	IncludeOEM        bool // Include OEM-specific structures (Type 128-255)
+	IncludeEndOfTable bool // Include the Type 127 End-of-Table marker
+}
+
+// DefaultStructureOptions returns options that select every structure
+// except inactive ones
+func DefaultStructureOptions() StructureOptions {
+	return StructureOptions{
+		IncludeOEM:        true,
+		IncludeEndOfTable: true,
+	}
+}
+
+// IsInactive returns true if the BIOS has marked this structure inactive (Type 126)
+func (s *Structure) IsInactive() bool {
+	return s.Header.Type == InactiveType
+}
+
+// IsOEM returns true if this is an OEM-specific structure (Type 128-255)
+func (s *Structure) IsOEM() bool {
+	return s.Header.Type >= OEMTypeMin
+}
+
+// CandidateTypes returns the structure types whose specification-defined length
+// matches this structure's formatted section. For an inactive structure this is
+// a best-effort hint at the original type, since the spec does not preserve it.
+// Types with variable-length layouts are never returned.
+func (s *Structure) CandidateTypes() []uint8 {
+	var result []uint8
+	for t := uint8(0); t < InactiveType; t++ {
+		for _, l := range specLengths[t] {
+			if l == s.Header.Length {
+				result = append(result, t)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// AsType returns a copy of the structure with its header type replaced.
+// This lets an inactive structure be inspected with a typeN.Parse function.
+// The formatted section is copied; the string table is shared with the original.
+func (s *Structure) AsType(structType uint8) Structure {
+	c := *s
+	c.Header.Type = structType
+	if len(s.Data) > 0 {
+		c.Data = make([]byte, len(s.Data))
+		copy(c.Data, s.Data)
+		c.Data[0] = structType
+	}
+	return c
+}
+
+// Select returns all structures accepted by the given options, in table order
+func (sm *SMBIOS) Select(opts StructureOptions) []Structure {
+	var result []Structure
+	for _, s := range sm.Structures {
+		switch {
+		case s.IsInactive() && !opts.IncludeInactive:
+			continue
+		case s.IsOEM() && !opts.IncludeOEM:
+			continue
+		case s.Header.Type == EndOfTableType && !opts.IncludeEndOfTable:
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// InactiveStructures returns all structures the BIOS has marked inactive
+func (sm *SMBIOS) InactiveStructures() []Structure {
+	return sm.GetStructures(InactiveType)
+}
+
+// GetStructuresWithInactive returns all structures of the specified type.
+// When includeInactive is set, inactive structures whose length matches the
+// type (see CandidateTypes) are appended, rewritten to structType with AsType
+// so they can be passed to the matching typeN.Parse. Use InactiveStructures
+// to tell them apart from active entries.
+func (sm *SMBIOS) GetStructuresWithInactive(structType uint8, includeInactive bool) []Structure {
+	result := sm.GetStructures(structType)
+	if !includeInactive {
+		return result
+	}
+	for _, s := range sm.InactiveStructures() {
+		for _, t := range s.CandidateTypes() {
+			if t == structType {
+				result = append(result, s.AsType(structType))
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,264 @@
+// Package jep106 decodes JEDEC JEP-106 manufacturer identification codes,
+// as used for memory modules, DRAM, PMICs and registering clock drivers in
+// SPD and SMBIOS Type 17, and for silicon providers in Arm SoC IDs.
+//
+// A code is a bank, numbered from 1, and a 7-bit identification code with
+// an odd parity bit. The first bank's codes are sent as is; a code in bank
+// n is preceded by n-1 continuation codes (0x7F). SMBIOS Type 17 stores a
+// code in a WORD: the low byte holds the number of continuation codes and
+// the high byte the identification code, each with its parity bit, so
+// Samsung (bank 1, 0xCE) is 0xCE80. Firmware often prints the two bytes in
+// that order as the Manufacturer string, e.g. "80CE".
+//
+// The embedded table covers the common DRAM, module and processor vendors.
+// It leaves out most DDR5 RCD and PMIC vendors, such as Montage, Rambus,
+// Richtek and MPS, whose codes print as a bank and code until Register
+// names them.
+package jep106
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Code is a JEP-106 manufacturer code
+type Code struct {
+	Bank uint8 // 1 for the first bank, one more than the continuation codes
+	ID   uint8 // Identification code; the parity bit is ignored
+}
+
+// FromWord decodes a code stored as in SMBIOS Type 17 and SPD: the number
+// of continuation codes in the low byte and the identification code in the
+// high byte. It returns false for 0, which means unknown.
+func FromWord(w uint16) (Code, bool) {
+	if w == 0 {
+		return Code{}, false
+	}
+	return Code{Bank: uint8(w&0x7F) + 1, ID: uint8(w>>8) & 0x7F}, true
+}
+
+// FromSiP decodes a code stored as in Arm SMCCC SoC IDs and Linux
+// "jep106:BBII" strings: the number of continuation codes << 8 | the
+// identification code without parity
+func FromSiP(sip uint16) Code {
+	return Code{Bank: uint8(sip>>8)&0x7F + 1, ID: uint8(sip) & 0x7F}
+}
+
+// IDWithParity returns the identification code with its odd parity bit
+func (c Code) IDWithParity() uint8 {
+	return withParity(c.ID)
+}
+
+// Word returns the code as stored in SMBIOS Type 17
+func (c Code) Word() uint16 {
+	if c.Bank == 0 {
+		return 0
+	}
+	return uint16(withParity(c.ID))<<8 | uint16(withParity(c.Bank-1))
+}
+
+// Name returns the manufacturer name, or an empty string if it is unknown
+func (c Code) Name() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return manufacturers[c.key()]
+}
+
+// String returns the manufacturer name, or the bank and code if the name
+// is unknown, e.g. "Bank 3, 0x9E"
+func (c Code) String() string {
+	if name := c.Name(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Bank %d, 0x%02X", c.Bank, c.IDWithParity())
+}
+
+func (c Code) key() Code {
+	return Code{Bank: c.Bank, ID: c.ID & 0x7F}
+}
+
+// withParity sets bit 7 when needed to give the byte odd parity
+func withParity(b uint8) uint8 {
+	b &= 0x7F
+	ones := 0
+	for v := b; v != 0; v >>= 1 {
+		ones += int(v & 1)
+	}
+	if ones%2 == 0 {
+		b |= 0x80
+	}
+	return b
+}
+
+// Lookup returns the manufacturer of a code stored as in SMBIOS Type 17,
+// an empty string for 0, or the bank and code if the name is unknown
+func Lookup(w uint16) string {
+	c, ok := FromWord(w)
+	if !ok {
+		return ""
+	}
+	return c.String()
+}
+
+// Parse decodes a code printed as hex digits, as firmware often does in
+// the Type 17 Manufacturer string. It accepts the SMBIOS byte order
+// ("80CE"), longer forms that repeat or pad it ("80CE000080CE"), and the
+// raw SPD bytes with continuation codes ("7F7F9E0000000000").
+func Parse(s string) (Code, bool) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "0X")
+	if len(s) < 4 || len(s)%2 != 0 {
+		return Code{}, false
+	}
+	b := make([]uint8, len(s)/2)
+	for i := range b {
+		v, err := strconv.ParseUint(s[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return Code{}, false
+		}
+		b[i] = uint8(v)
+	}
+
+	// Continuation codes followed by the identification code
+	if b[0] == 0x7F {
+		n := 0
+		for n < len(b) && b[n] == 0x7F {
+			n++
+		}
+		if n < len(b) && b[n] != 0 {
+			return Code{Bank: uint8(n) + 1, ID: b[n] & 0x7F}, true
+		}
+		return Code{}, false
+	}
+	// Identification code of the first bank followed by zeros
+	if b[1] == 0 && b[0] != 0 && withParity(b[0]) == b[0] {
+		if len(b) == 2 || b[0]&0x7F > 15 {
+			return Code{Bank: 1, ID: b[0] & 0x7F}, true
+		}
+	}
+	// Continuation count and identification code, each with parity
+	if b[1] != 0 && b[0]&0x7F < 16 && withParity(b[0]) == b[0] && withParity(b[1]) == b[1] {
+		return Code{Bank: b[0]&0x7F + 1, ID: b[1] & 0x7F}, true
+	}
+	return Code{}, false
+}
+
+// aliases map lower-case fragments of Manufacturer strings to canonical
+// vendor names
+var aliases = []struct{ fragment, name string }{
+	{"samsung", "Samsung"},
+	{"hynix", "SK Hynix"},
+	{"hyundai", "SK Hynix"},
+	{"micron", "Micron"},
+	{"crucial", "Crucial"},
+	{"kingston", "Kingston"},
+	{"nanya", "Nanya"},
+	{"elpida", "Elpida"},
+	{"qimonda", "Qimonda"},
+	{"corsair", "Corsair"},
+	{"g.skill", "G.Skill"},
+	{"g skill", "G.Skill"},
+	{"gskill", "G.Skill"},
+	{"a-data", "ADATA"},
+	{"adata", "ADATA"},
+	{"smart modular", "Smart Modular"},
+	{"transcend", "Transcend"},
+	{"apacer", "Apacer"},
+	{"team group", "Team Group"},
+	{"teamgroup", "Team Group"},
+	{"kioxia", "Kioxia"},
+	{"toshiba", "Kioxia"},
+	{"infineon", "Infineon"},
+	{"intel", "Intel"},
+}
+
+// unknownNames are Manufacturer strings that name no vendor
+var unknownNames = map[string]bool{
+	"":              true,
+	"unknown":       true,
+	"not specified": true,
+	"undefined":     true,
+	"none":          true,
+	"0000":          true,
+}
+
+// Normalize returns the canonical vendor name of a Manufacturer string,
+// decoding hex codes such as "80CE" and folding spellings such as
+// "Samsung Electronics" or "Hynix Semiconductor". It returns the trimmed
+// string if it names no known vendor, and an empty string for placeholders
+// such as "Unknown" or "Manufacturer00".
+func Normalize(manufacturer string) string {
+	s := strings.TrimSpace(manufacturer)
+	lower := strings.ToLower(s)
+	if unknownNames[lower] || strings.HasPrefix(lower, "manufacturer") {
+		return ""
+	}
+	if c, ok := Parse(s); ok {
+		return c.String()
+	}
+	for _, a := range aliases {
+		if strings.Contains(lower, a.fragment) {
+			return a.name
+		}
+	}
+	return s
+}
+
+// Register names a manufacturer code, replacing any existing name
+func Register(c Code, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	manufacturers[c.key()] = name
+}
+
+var (
+	mu sync.RWMutex
+
+	// manufacturers are the names by bank and identification code without
+	// its parity bit
+	manufacturers = map[Code]string{
+		// Bank 1
+		{1, 0x01}: "AMD",
+		{1, 0x02}: "AMI",
+		{1, 0x04}: "Fujitsu",
+		{1, 0x07}: "Hitachi",
+		{1, 0x09}: "Intel",
+		{1, 0x10}: "NEC",
+		{1, 0x15}: "NXP",
+		{1, 0x17}: "Texas Instruments",
+		{1, 0x18}: "Kioxia",
+		{1, 0x1C}: "Mitsubishi",
+		{1, 0x20}: "STMicroelectronics",
+		{1, 0x2C}: "Micron",
+		{1, 0x2D}: "SK Hynix",
+		{1, 0x33}: "IDT",
+		{1, 0x3F}: "SST",
+		{1, 0x41}: "Infineon",
+		{1, 0x42}: "Macronix",
+		{1, 0x4E}: "Samsung",
+		{1, 0x4F}: "Transcend",
+		{1, 0x5A}: "Winbond",
+		{1, 0x70}: "Qualcomm",
+		{1, 0x7E}: "Elpida",
+		// Bank 2
+		{2, 0x14}: "Smart Modular",
+		{2, 0x18}: "Kingston",
+		{2, 0x51}: "Qimonda",
+		{2, 0x7A}: "Apacer",
+		// Bank 3
+		{3, 0x1E}: "Corsair",
+		// Bank 4
+		{4, 0x0B}: "Nanya",
+		{4, 0x6B}: "NVIDIA",
+		// Bank 5
+		{5, 0x3B}: "Arm",
+		{5, 0x4B}: "ADATA",
+		{5, 0x4D}: "G.Skill",
+		{5, 0x6F}: "Team Group",
+		// Bank 6
+		{6, 0x1B}: "Crucial",
+		// Bank 11
+		{11, 0x16}: "Ampere",
+	}
+)
@@ -0,0 +1,439 @@
+// Package libvirt converts between SMBIOS tables and the libvirt domain XML
+// <sysinfo type='smbios'> block.
+//
+// FromSMBIOS produces a sysinfo block from a decoded table, so a guest can
+// mirror a physical host. Parse reads a sysinfo block (bare, or inside a
+// <domain>), and Table builds the synthetic structures it describes, so the
+// guest's actual table can be checked against what was configured with Verify.
+//
+// libvirt only applies the block when the domain has <os><smbios mode='sysinfo'/></os>.
+package libvirt
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type11"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type3"
+)
+
+// SysinfoType is the sysinfo type attribute for SMBIOS data
+const SysinfoType = "smbios"
+
+// ErrNoSysinfo is returned when the XML has no <sysinfo type='smbios'> block
+var ErrNoSysinfo = errors.New("smbios: no sysinfo type='smbios' block in XML")
+
+// Entry is a single <entry> element. OEM strings have no name.
+type Entry struct {
+	Name  string `xml:"name,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// Block is a group of entries, such as <bios> or <system>
+type Block struct {
+	Entries []Entry `xml:"entry"`
+}
+
+// Get returns the value of the named entry, or an empty string
+func (b *Block) Get(name string) string {
+	if b == nil {
+		return ""
+	}
+	for _, e := range b.Entries {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// set appends a named entry, skipping empty values
+func (b *Block) set(name, value string) {
+	if value != "" {
+		b.Entries = append(b.Entries, Entry{Name: name, Value: value})
+	}
+}
+
+// orNil returns nil for a block without entries, so it is omitted from XML
+func (b *Block) orNil() *Block {
+	if len(b.Entries) == 0 {
+		return nil
+	}
+	return b
+}
+
+// Sysinfo is a <sysinfo type='smbios'> block
+type Sysinfo struct {
+	XMLName    xml.Name `xml:"sysinfo"`
+	Type       string   `xml:"type,attr"`
+	BIOS       *Block   `xml:"bios,omitempty"`
+	System     *Block   `xml:"system,omitempty"`
+	BaseBoards []Block  `xml:"baseBoard,omitempty"`
+	Chassis    *Block   `xml:"chassis,omitempty"`
+	OEMStrings *Block   `xml:"oemStrings,omitempty"`
+}
+
+// domain holds the parts of a libvirt domain definition used here
+type domain struct {
+	XMLName xml.Name  `xml:"domain"`
+	UUID    string    `xml:"uuid"`
+	Sysinfo []Sysinfo `xml:"sysinfo"`
+}
+
+// FromSMBIOS builds a sysinfo block from the Type 0, 1, 2, 3 and 11
+// structures of a table. Empty fields are left out.
+func FromSMBIOS(sm *gosmbios.SMBIOS) *Sysinfo {
+	si := &Sysinfo{Type: SysinfoType}
+
+	if bios, err := type0.Get(sm); err == nil {
+		b := &Block{}
+		b.set("vendor", bios.Vendor)
+		b.set("version", bios.Version)
+		b.set("date", bios.ReleaseDate)
+		if bios.SystemBIOSMajorRelease != 0xFF {
+			b.set("release", bios.BIOSVersionString())
+		}
+		si.BIOS = b.orNil()
+	}
+
+	if sys, err := type1.Get(sm); err == nil {
+		b := &Block{}
+		b.set("manufacturer", sys.Manufacturer)
+		b.set("product", sys.ProductName)
+		b.set("version", sys.Version)
+		b.set("serial", sys.SerialNumber)
+		if !sys.UUID.IsZero() {
+			b.set("uuid", strings.ToLower(sys.UUID.String()))
+		}
+		b.set("sku", sys.SKUNumber)
+		b.set("family", sys.Family)
+		si.System = b.orNil()
+	}
+
+	if boards, err := type2.GetAll(sm); err == nil {
+		for _, board := range boards {
+			b := &Block{}
+			b.set("manufacturer", board.Manufacturer)
+			b.set("product", board.Product)
+			b.set("version", board.Version)
+			b.set("serial", board.SerialNumber)
+			b.set("asset", board.AssetTag)
+			b.set("location", board.LocationInChassis)
+			if b.orNil() != nil {
+				si.BaseBoards = append(si.BaseBoards, *b)
+			}
+		}
+	}
+
+	if chassis, err := type3.Get(sm); err == nil {
+		b := &Block{}
+		b.set("manufacturer", chassis.Manufacturer)
+		b.set("version", chassis.Version)
+		b.set("serial", chassis.SerialNumber)
+		b.set("asset", chassis.AssetTag)
+		b.set("sku", chassis.SKUNumber)
+		si.Chassis = b.orNil()
+	}
+
+	if oems, err := type11.GetAll(sm); err == nil {
+		b := &Block{}
+		for _, oem := range oems {
+			for _, s := range oem.Strings {
+				if s != "" {
+					b.Entries = append(b.Entries, Entry{Value: s})
+				}
+			}
+		}
+		si.OEMStrings = b.orNil()
+	}
+
+	return si
+}
+
+// Marshal returns the sysinfo block as indented XML
+func (si *Sysinfo) Marshal() ([]byte, error) {
+	return xml.MarshalIndent(si, "", "  ")
+}
+
+// Parse reads a <sysinfo type='smbios'> block, either on its own or from a
+// full <domain> definition. For a domain without a system uuid entry, the
+// domain <uuid> is used, as libvirt does.
+func Parse(data []byte) (*Sysinfo, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	switch root.XMLName.Local {
+	case "sysinfo":
+		var si Sysinfo
+		if err := xml.Unmarshal(data, &si); err != nil {
+			return nil, err
+		}
+		if si.Type != SysinfoType {
+			return nil, ErrNoSysinfo
+		}
+		return &si, nil
+
+	case "domain":
+		var d domain
+		if err := xml.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+		for i := range d.Sysinfo {
+			si := &d.Sysinfo[i]
+			if si.Type != SysinfoType {
+				continue
+			}
+			if si.System.Get("uuid") == "" && d.UUID != "" {
+				if si.System == nil {
+					si.System = &Block{}
+				}
+				si.System.set("uuid", d.UUID)
+			}
+			return si, nil
+		}
+	}
+	return nil, ErrNoSysinfo
+}
+
+// BIOSInfo returns the Type 0 structure described by the <bios> block,
+// or nil if there is none
+func (si *Sysinfo) BIOSInfo() (*type0.BIOSInfo, error) {
+	if si.BIOS == nil {
+		return nil, nil
+	}
+	bios := &type0.BIOSInfo{
+		Vendor:                         si.BIOS.Get("vendor"),
+		Version:                        si.BIOS.Get("version"),
+		ReleaseDate:                    si.BIOS.Get("date"),
+		Characteristics:                type0.CharNotSupported,
+		SystemBIOSMajorRelease:         0xFF,
+		SystemBIOSMinorRelease:         0xFF,
+		EmbeddedControllerMajorRelease: 0xFF,
+		EmbeddedControllerMinorRelease: 0xFF,
+	}
+	if release := si.BIOS.Get("release"); release != "" {
+		var major, minor uint8
+		if _, err := fmt.Sscanf(release, "%d.%d", &major, &minor); err != nil {
+			return nil, fmt.Errorf("smbios: invalid bios release %q", release)
+		}
+		bios.SystemBIOSMajorRelease = major
+		bios.SystemBIOSMinorRelease = minor
+	}
+	return bios, nil
+}
+
+// SystemInfo returns the Type 1 structure described by the <system> block,
+// or nil if there is none
+func (si *Sysinfo) SystemInfo() (*type1.SystemInfo, error) {
+	if si.System == nil {
+		return nil, nil
+	}
+	sys := &type1.SystemInfo{
+		Manufacturer: si.System.Get("manufacturer"),
+		ProductName:  si.System.Get("product"),
+		Version:      si.System.Get("version"),
+		SerialNumber: si.System.Get("serial"),
+		WakeUpType:   type1.WakeUpPowerSwitch,
+		SKUNumber:    si.System.Get("sku"),
+		Family:       si.System.Get("family"),
+	}
+	if uuid := si.System.Get("uuid"); uuid != "" {
+		u, err := type1.ParseUUID(uuid)
+		if err != nil {
+			return nil, err
+		}
+		sys.UUID = u
+	}
+	return sys, nil
+}
+
+// BaseboardInfo returns the Type 2 structures described by the <baseBoard>
+// blocks
+func (si *Sysinfo) BaseboardInfo() []*type2.BaseboardInfo {
+	var boards []*type2.BaseboardInfo
+	for i := range si.BaseBoards {
+		b := &si.BaseBoards[i]
+		boards = append(boards, &type2.BaseboardInfo{
+			Manufacturer:      b.Get("manufacturer"),
+			Product:           b.Get("product"),
+			Version:           b.Get("version"),
+			SerialNumber:      b.Get("serial"),
+			AssetTag:          b.Get("asset"),
+			FeatureFlags:      type2.FeatureHostingBoard,
+			LocationInChassis: b.Get("location"),
+			BoardType:         type2.BoardTypeMotherboard,
+		})
+	}
+	return boards
+}
+
+// ChassisInfo returns the Type 3 structure described by the <chassis> block,
+// or nil if there is none
+func (si *Sysinfo) ChassisInfo() *type3.ChassisInfo {
+	if si.Chassis == nil {
+		return nil
+	}
+	return &type3.ChassisInfo{
+		Manufacturer:     si.Chassis.Get("manufacturer"),
+		Type:             type3.ChassisTypeOther,
+		Version:          si.Chassis.Get("version"),
+		SerialNumber:     si.Chassis.Get("serial"),
+		AssetTag:         si.Chassis.Get("asset"),
+		BootUpState:      type3.ChassisStateSafe,
+		PowerSupplyState: type3.ChassisStateSafe,
+		ThermalState:     type3.ChassisStateSafe,
+		SecurityStatus:   type3.SecurityNone,
+		SKUNumber:        si.Chassis.Get("sku"),
+	}
+}
+
+// OEMStringValues returns the <oemStrings> entries
+func (si *Sysinfo) OEMStringValues() []string {
+	if si.OEMStrings == nil {
+		return nil
+	}
+	var strs []string
+	for _, e := range si.OEMStrings.Entries {
+		strs = append(strs, e.Value)
+	}
+	return strs
+}
+
+// Table builds a synthetic SMBIOS table holding the structures described by
+// the sysinfo block, terminated by an End-of-Table structure
+func (si *Sysinfo) Table() (*gosmbios.SMBIOS, error) {
+	var structures []gosmbios.Structure
+	add := func(s gosmbios.Structure, err error) error {
+		if err == nil {
+			structures = append(structures, s)
+		}
+		return err
+	}
+
+	bios, err := si.BIOSInfo()
+	if err != nil {
+		return nil, err
+	}
+	if bios != nil {
+		if err := add(bios.Build(0x0000)); err != nil {
+			return nil, err
+		}
+	}
+
+	sys, err := si.SystemInfo()
+	if err != nil {
+		return nil, err
+	}
+	if sys != nil {
+		if err := add(sys.Build(0x0100)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, board := range si.BaseboardInfo() {
+		if err := add(board.Build(0x0200 + uint16(i))); err != nil {
+			return nil, err
+		}
+	}
+
+	if chassis := si.ChassisInfo(); chassis != nil {
+		if err := add(chassis.Build(0x0300)); err != nil {
+			return nil, err
+		}
+	}
+
+	if strs := si.OEMStringValues(); len(strs) > 0 {
+		if err := add(type11.Build(0x0B00, strs)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := add(gosmbios.NewStructure(gosmbios.EndOfTableType, 0x7F00, nil, nil)); err != nil {
+		return nil, err
+	}
+
+	sm := &gosmbios.SMBIOS{
+		EntryPoint: gosmbios.EntryPoint{
+			Type:         gosmbios.EntryPoint64Bit,
+			MajorVersion: 3,
+			MinorVersion: 0,
+		},
+		Structures: structures,
+	}
+	sm.EntryPoint.TableLength = uint32(len(sm.TableBytes()))
+	return sm, nil
+}
+
+// Mismatch is a configured sysinfo value that differs from the actual table
+type Mismatch struct {
+	Block string // bios, system, baseBoard[N], chassis or oemStrings
+	Entry string // Entry name (empty for OEM strings)
+	Want  string // Configured value
+	Got   string // Value found in the table (empty if missing)
+}
+
+// String returns a human-readable description of the mismatch
+func (m Mismatch) String() string {
+	if m.Entry == "" {
+		return fmt.Sprintf("%s: %q not found", m.Block, m.Want)
+	}
+	return fmt.Sprintf("%s/%s: want %q, got %q", m.Block, m.Entry, m.Want, m.Got)
+}
+
+// Verify checks that every value configured in want is present in the actual
+// table, such as one read inside the guest. Values the table has but want
+// does not configure are not reported. An empty result means the guest
+// matches its configuration.
+func Verify(want *Sysinfo, sm *gosmbios.SMBIOS) []Mismatch {
+	got := FromSMBIOS(sm)
+	var mismatches []Mismatch
+
+	compare := func(name string, w, g *Block) {
+		if w == nil {
+			return
+		}
+		for _, e := range w.Entries {
+			actual := g.Get(e.Name)
+			equal := actual == e.Value
+			if e.Name == "uuid" {
+				equal = strings.EqualFold(actual, e.Value)
+			}
+			if !equal {
+				mismatches = append(mismatches, Mismatch{Block: name, Entry: e.Name, Want: e.Value, Got: actual})
+			}
+		}
+	}
+
+	compare("bios", want.BIOS, got.BIOS)
+	compare("system", want.System, got.System)
+	for i := range want.BaseBoards {
+		var g *Block
+		if i < len(got.BaseBoards) {
+			g = &got.BaseBoards[i]
+		}
+		compare(fmt.Sprintf("baseBoard[%d]", i), &want.BaseBoards[i], g)
+	}
+	compare("chassis", want.Chassis, got.Chassis)
+
+	present := make(map[string]bool)
+	for _, s := range got.OEMStringValues() {
+		present[s] = true
+	}
+	for _, s := range want.OEMStringValues() {
+		if !present[s] {
+			mismatches = append(mismatches, Mismatch{Block: "oemStrings", Want: s})
+		}
+	}
+
+	return mismatches
+}
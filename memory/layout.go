@@ -0,0 +1,251 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type16"
+	"github.com/earentir/gosmbios/types/type37"
+)
+
+// Position is where a device sits. SMBIOS only records sockets and channels
+// in Memory Channel (Type 37) structures, which few systems provide, so they
+// are usually taken from the vendor's locator strings, such as
+// "CPU1_DIMM_A1", "P0 CHANNEL A" or "ChannelB-DIMM0". Fields not found are
+// empty.
+type Position struct {
+	Socket  string // e.g. "CPU1"
+	Channel string // e.g. "A"
+	Slot    string // Slot within the channel, e.g. "1"
+}
+
+// ChannelKey returns a key naming the channel across sockets, or an empty
+// string if the channel is unknown
+func (p Position) ChannelKey() string {
+	if p.Channel == "" {
+		return ""
+	}
+	if p.Socket == "" {
+		return "Channel " + p.Channel
+	}
+	return p.Socket + " Channel " + p.Channel
+}
+
+// SocketName returns the socket, or "System" if it is unknown
+func (p Position) SocketName() string {
+	if p.Socket == "" {
+		return "System"
+	}
+	return p.Socket
+}
+
+var (
+	socketPattern     = regexp.MustCompile(`(?:^|[^A-Z])(CPU|PROC|PROCESSOR|SOCKET|P)\s*[-_]?\s*(\d+)`)
+	nodePattern       = regexp.MustCompile(`NODE\s*[-_]?\s*(\d+)`)
+	channelPattern    = regexp.MustCompile(`CHANNEL\s*[-_]?\s*([A-Z0-9]+)`)
+	controllerPattern = regexp.MustCompile(`CONTROLLER\s*[-_]?\s*(\d+)`)
+	dimmPattern       = regexp.MustCompile(`DIMM\s*[-_]?\s*([A-Z])\s*[-_]?\s*(\d+)`)
+	slotPattern       = regexp.MustCompile(`DIMM\s*[-_]?\s*(\d+)`)
+)
+
+// ParsePosition infers the position of a device from its device and bank
+// locators
+func ParsePosition(deviceLocator, bankLocator string) Position {
+	var p Position
+	locators := []string{strings.ToUpper(deviceLocator), strings.ToUpper(bankLocator)}
+	for _, l := range locators {
+		if p.Socket == "" {
+			if m := socketPattern.FindStringSubmatch(l); m != nil {
+				p.Socket = "CPU" + m[2]
+				if m[1] == "P" {
+					// AMD and Supermicro number sockets from P0 or P1
+					p.Socket = "P" + m[2]
+				}
+			}
+		}
+		if p.Channel == "" {
+			if m := channelPattern.FindStringSubmatch(l); m != nil {
+				p.Channel = m[1]
+			}
+		}
+		if m := dimmPattern.FindStringSubmatch(l); m != nil {
+			if p.Channel == "" {
+				p.Channel = m[1]
+			}
+			if p.Slot == "" {
+				p.Slot = m[2]
+			}
+		}
+		if p.Slot == "" {
+			if m := slotPattern.FindStringSubmatch(l); m != nil {
+				p.Slot = m[1]
+			}
+		}
+	}
+	if p.Channel != "" {
+		for _, l := range locators {
+			if m := controllerPattern.FindStringSubmatch(l); m != nil {
+				// Channels are named per controller, e.g. Controller1-ChannelA
+				p.Channel = m[1] + p.Channel
+				break
+			}
+		}
+	}
+	if p.Socket == "" {
+		for _, l := range locators {
+			if m := nodePattern.FindStringSubmatch(l); m != nil {
+				p.Socket = "Node" + m[1]
+				break
+			}
+		}
+	}
+	return p
+}
+
+// assignPositions sets the position of each device, using Type 37 channels
+// when the table has them and one array per socket when the locators do
+// not name sockets
+func (t *Topology) assignPositions(sm *gosmbios.SMBIOS) {
+	channels := make(map[uint16]string)
+	if infos, err := type37.GetAll(sm); err == nil {
+		for i, info := range infos {
+			for _, d := range info.MemoryDevices {
+				channels[d.MemoryDeviceHandle] = fmt.Sprint(i + 1)
+			}
+		}
+	}
+
+	arrays := t.systemArrays()
+	for _, d := range t.Devices {
+		d.Position = ParsePosition(d.Info.DeviceLocator, d.Info.BankLocator)
+		if ch, ok := channels[d.Handle]; ok {
+			d.Position.Channel = ch
+		}
+		if d.Position.Socket == "" && len(arrays) > 1 && d.Array != nil {
+			for i, a := range arrays {
+				if a == d.Array {
+					d.Position.Socket = fmt.Sprintf("Array %d", i+1)
+				}
+			}
+		}
+	}
+}
+
+// systemArrays returns the arrays used for system memory
+func (t *Topology) systemArrays() []*Array {
+	var arrays []*Array
+	for _, a := range t.Arrays {
+		if a.Info.Use == type16.UseSystemMemory {
+			arrays = append(arrays, a)
+		}
+	}
+	return arrays
+}
+
+// SystemDevices returns the slots of system memory, leaving out devices of
+// arrays used for video, flash or cache memory
+func (t *Topology) SystemDevices() []*Device {
+	var devices []*Device
+	for _, d := range t.Devices {
+		if d.Array == nil || d.Array.Info.Use == type16.UseSystemMemory || d.Array.Info.Use == type16.UseUnknown {
+			devices = append(devices, d)
+		}
+	}
+	return devices
+}
+
+// Socket is the memory slots of one processor socket
+type Socket struct {
+	Name     string
+	Devices  []*Device
+	Channels []*Channel // Empty if the channels are unknown
+}
+
+// Channel is the memory slots of one channel
+type Channel struct {
+	Name    string
+	Devices []*Device
+}
+
+// Capacity returns the installed memory of the channel in MB
+func (c *Channel) Capacity() uint64 {
+	return capacity(c.Devices)
+}
+
+// Populated returns the number of installed modules
+func (c *Channel) Populated() int {
+	return len(populated(c.Devices))
+}
+
+// Capacity returns the installed memory of the socket in MB
+func (s *Socket) Capacity() uint64 {
+	return capacity(s.Devices)
+}
+
+// Sockets groups the system memory slots by socket and channel. Channels
+// are only listed when every slot of the socket has a known channel.
+func (t *Topology) Sockets() []*Socket {
+	var sockets []*Socket
+	byName := make(map[string]*Socket)
+	for _, d := range t.SystemDevices() {
+		name := d.Position.SocketName()
+		s, ok := byName[name]
+		if !ok {
+			s = &Socket{Name: name}
+			byName[name] = s
+			sockets = append(sockets, s)
+		}
+		s.Devices = append(s.Devices, d)
+	}
+
+	for _, s := range sockets {
+		byChannel := make(map[string]*Channel)
+		for _, d := range s.Devices {
+			if d.Position.Channel == "" {
+				s.Channels = nil
+				break
+			}
+			c, ok := byChannel[d.Position.Channel]
+			if !ok {
+				c = &Channel{Name: d.Position.Channel}
+				byChannel[c.Name] = c
+				s.Channels = append(s.Channels, c)
+			}
+			c.Devices = append(c.Devices, d)
+		}
+		sort.SliceStable(s.Channels, func(i, j int) bool { return s.Channels[i].Name < s.Channels[j].Name })
+	}
+	sort.SliceStable(sockets, func(i, j int) bool { return sockets[i].Name < sockets[j].Name })
+	return sockets
+}
+
+// populated returns the devices with a module installed
+func populated(devices []*Device) []*Device {
+	var out []*Device
+	for _, d := range devices {
+		if d.Info.IsPopulated() {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// capacity returns the installed memory of devices in MB
+func capacity(devices []*Device) uint64 {
+	var total uint64
+	for _, d := range devices {
+		total += d.Info.Size
+	}
+	return total
+}
+
+// formatMB formats a size in megabytes
+func formatMB(mb uint64) string {
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%d GB", mb/1024)
+	}
+	return fmt.Sprintf("%d MB", mb)
+}
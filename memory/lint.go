@@ -0,0 +1,299 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type16"
+)
+
+// Severity is how much a finding is likely to matter
+type Severity string
+
+// Severities
+const (
+	Warning Severity = "warning" // Costs performance or error protection
+	Notice  Severity = "notice"  // Worth checking, but often intended
+)
+
+// Checks reported in findings
+const (
+	CheckChannelBalance  = "channel-balance"
+	CheckSocketBalance   = "socket-balance"
+	CheckMixedSpeed      = "mixed-speed"
+	CheckMixedRanks      = "mixed-ranks"
+	CheckMixedPartNumber = "mixed-part-number"
+	CheckMixedVoltage    = "mixed-voltage"
+	CheckConfiguredSpeed = "configured-speed"
+	CheckECCDisabled     = "ecc-disabled"
+	CheckECCUnavailable  = "ecc-unavailable"
+	CheckWidth           = "width"
+)
+
+// Finding is a problem with the memory configuration
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Slots    []string `json:"slots"` // Locators of the affected slots
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s (slots: %s)", f.Severity, f.Check, f.Message, strings.Join(f.Slots, ", "))
+}
+
+// Lint checks the system memory for unbalanced population, mixed modules
+// and modules running degraded, such as below their rated speed or without
+// error correction
+func (t *Topology) Lint() []Finding {
+	findings := []Finding{}
+	add := func(f *Finding) {
+		if f != nil {
+			findings = append(findings, *f)
+		}
+	}
+
+	sockets := t.Sockets()
+	for _, s := range sockets {
+		add(lintChannels(s))
+	}
+	add(lintSockets(sockets))
+
+	devices := populated(t.SystemDevices())
+	add(mixed(CheckMixedSpeed, Warning, "modules of different speeds run at the speed of the slowest", devices, func(d *Device) string {
+		if d.Info.GetSpeed() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d MT/s", d.Info.GetSpeed())
+	}))
+	add(mixed(CheckMixedRanks, Warning, "mixing rank counts can lower the supported speed and interleaving", devices, func(d *Device) string {
+		switch r := d.Info.Ranks(); r {
+		case 0:
+			return ""
+		case 1:
+			return "1 rank"
+		default:
+			return fmt.Sprintf("%d ranks", r)
+		}
+	}))
+	add(mixed(CheckMixedPartNumber, Notice, "modules are not all the same part", devices, func(d *Device) string {
+		return gosmbios.Sanitize(d.Info.PartNumber)
+	}))
+	add(mixed(CheckMixedVoltage, Warning, "modules are configured for different voltages", devices, func(d *Device) string {
+		if d.Info.ConfiguredVoltage == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.2f V", float64(d.Info.ConfiguredVoltage)/1000.0)
+	}))
+	add(lintConfiguredSpeed(devices))
+	findings = append(findings, lintECC(devices)...)
+	add(lintWidth(devices))
+	return findings
+}
+
+// lintChannels reports channels of a socket that hold less memory or fewer
+// modules than the others
+func lintChannels(s *Socket) *Finding {
+	if len(s.Channels) < 2 {
+		return nil
+	}
+	var most *Channel
+	balanced := true
+	for _, c := range s.Channels {
+		if most == nil || c.Capacity() > most.Capacity() {
+			most = c
+		}
+	}
+	var parts, slots []string
+	for _, c := range s.Channels {
+		parts = append(parts, fmt.Sprintf("%s %s in %d", c.Name, formatMB(c.Capacity()), c.Populated()))
+		if c.Capacity() != most.Capacity() || c.Populated() != most.Populated() {
+			balanced = false
+			slots = append(slots, locators(c.Devices)...)
+		}
+	}
+	if balanced {
+		return nil
+	}
+	return &Finding{
+		Check:    CheckChannelBalance,
+		Severity: Warning,
+		Message:  fmt.Sprintf("%s channels are unbalanced, which limits interleaving (%s)", s.Name, strings.Join(parts, ", ")),
+		Slots:    slots,
+	}
+}
+
+// lintSockets reports sockets that hold less memory than the others
+func lintSockets(sockets []*Socket) *Finding {
+	if len(sockets) < 2 {
+		return nil
+	}
+	var most uint64
+	for _, s := range sockets {
+		if s.Capacity() > most {
+			most = s.Capacity()
+		}
+	}
+	var parts, slots []string
+	for _, s := range sockets {
+		parts = append(parts, fmt.Sprintf("%s %s", s.Name, formatMB(s.Capacity())))
+		if s.Capacity() != most {
+			slots = append(slots, locators(s.Devices)...)
+		}
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	return &Finding{
+		Check:    CheckSocketBalance,
+		Severity: Warning,
+		Message:  fmt.Sprintf("sockets hold different amounts of memory, so some processes use remote memory (%s)", strings.Join(parts, ", ")),
+		Slots:    slots,
+	}
+}
+
+// mixed reports devices whose key differs, naming the slots outside the
+// largest group. Devices with an empty key are not compared.
+func mixed(check string, severity Severity, why string, devices []*Device, key func(*Device) string) *Finding {
+	groups := make(map[string][]*Device)
+	var keys []string
+	for _, d := range devices {
+		k := key(d)
+		if k == "" {
+			continue
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], d)
+	}
+	if len(keys) < 2 {
+		return nil
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return len(groups[keys[i]]) > len(groups[keys[j]]) })
+
+	var parts, slots []string
+	for i, k := range keys {
+		if i == 0 {
+			parts = append(parts, fmt.Sprintf("%s in %d slot(s)", k, len(groups[k])))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", k, strings.Join(locators(groups[k]), ", ")))
+		slots = append(slots, locators(groups[k])...)
+	}
+	return &Finding{
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf("%s (%s)", why, strings.Join(parts, "; ")),
+		Slots:    slots,
+	}
+}
+
+// lintConfiguredSpeed reports modules configured below their rated speed
+func lintConfiguredSpeed(devices []*Device) *Finding {
+	var parts, slots []string
+	for _, d := range devices {
+		rated, configured := d.Info.GetSpeed(), d.Info.GetConfiguredSpeed()
+		if rated == 0 || configured == 0 || configured >= rated {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %d of %d MT/s", d.Locator(), configured, rated))
+		slots = append(slots, d.Locator())
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	return &Finding{
+		Check:    CheckConfiguredSpeed,
+		Severity: Warning,
+		Message:  fmt.Sprintf("modules run below their rated speed (%s)", strings.Join(parts, ", ")),
+		Slots:    slots,
+	}
+}
+
+// hasECC reports whether a module has check bits, and whether its widths
+// are known
+func hasECC(d *Device) (ecc, known bool) {
+	total, data := d.Info.TotalWidth, d.Info.DataWidth
+	if total == 0 || total == 0xFFFF || data == 0 || data == 0xFFFF {
+		return false, false
+	}
+	return total > data, true
+}
+
+// lintECC reports modules whose error correction does not match the array:
+// ECC modules in an array without error correction, where ECC is disabled,
+// and non-ECC modules in an ECC array, which cannot provide it
+func lintECC(devices []*Device) []Finding {
+	var disabled, unavailable []string
+	for _, d := range devices {
+		ecc, known := hasECC(d)
+		if !known || d.Array == nil {
+			continue
+		}
+		switch d.Array.Info.ErrorCorrection {
+		case type16.ErrorCorrectionNone:
+			if ecc {
+				disabled = append(disabled, d.Locator())
+			}
+		case type16.ErrorCorrectionSingleBitECC, type16.ErrorCorrectionMultiBitECC, type16.ErrorCorrectionCRC:
+			if !ecc {
+				unavailable = append(unavailable, d.Locator())
+			}
+		}
+	}
+	var findings []Finding
+	if len(disabled) > 0 {
+		findings = append(findings, Finding{
+			Check:    CheckECCDisabled,
+			Severity: Warning,
+			Message:  "ECC modules are installed but the memory array reports no error correction, so ECC is disabled",
+			Slots:    disabled,
+		})
+	}
+	if len(unavailable) > 0 {
+		findings = append(findings, Finding{
+			Check:    CheckECCUnavailable,
+			Severity: Warning,
+			Message:  "the memory array reports error correction but these modules have no check bits",
+			Slots:    unavailable,
+		})
+	}
+	return findings
+}
+
+// lintWidth reports modules with a total width below the data width, or
+// with different widths than the other modules
+func lintWidth(devices []*Device) *Finding {
+	var invalid []string
+	for _, d := range devices {
+		if _, known := hasECC(d); known && d.Info.TotalWidth < d.Info.DataWidth {
+			invalid = append(invalid, d.Locator())
+		}
+	}
+	if len(invalid) > 0 {
+		return &Finding{
+			Check:    CheckWidth,
+			Severity: Notice,
+			Message:  "total width is below the data width, so the firmware reports the widths wrongly",
+			Slots:    invalid,
+		}
+	}
+	return mixed(CheckWidth, Warning, "modules report different widths, and mixing ECC and non-ECC modules disables ECC", devices, func(d *Device) string {
+		if _, known := hasECC(d); !known {
+			return ""
+		}
+		return fmt.Sprintf("%d/%d bits", d.Info.TotalWidth, d.Info.DataWidth)
+	})
+}
+
+// locators returns the locators of devices
+func locators(devices []*Device) []string {
+	out := make([]string, len(devices))
+	for i, d := range devices {
+		out[i] = d.Locator()
+	}
+	return out
+}
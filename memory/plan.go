@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type17"
+)
+
+// Module describes the modules that can be added alongside the installed
+// ones: same type, form factor, buffering, ECC and ranks, and at least the
+// configured speed
+type Module struct {
+	Type       type17.MemoryType       `json:"-"`
+	FormFactor type17.MemoryFormFactor `json:"-"`
+	Buffering  string                  `json:"buffering,omitempty"` // "Registered", "LRDIMM" or "Unbuffered"
+	ECC        bool                    `json:"ecc"`
+	Ranks      uint8                   `json:"ranks,omitempty"`
+	Size       uint64                  `json:"size_mb"`             // In MB
+	MinSpeed   uint32                  `json:"min_speed,omitempty"` // In MT/s
+	PartNumber string                  `json:"part_number,omitempty"`
+}
+
+// MarshalJSON encodes the type and form factor by name
+func (m Module) MarshalJSON() ([]byte, error) {
+	type module Module
+	return json.Marshal(struct {
+		Type       string `json:"type"`
+		FormFactor string `json:"form_factor"`
+		module
+	}{m.Type.String(), m.FormFactor.String(), module(m)})
+}
+
+func (m *Module) String() string {
+	parts := []string{formatMB(m.Size), m.Type.String(), m.FormFactor.String()}
+	if m.Buffering != "" {
+		parts = append(parts, m.Buffering)
+	}
+	if m.ECC {
+		parts = append(parts, "ECC")
+	} else {
+		parts = append(parts, "non-ECC")
+	}
+	if m.Ranks > 0 {
+		parts = append(parts, fmt.Sprintf("%dR", m.Ranks))
+	}
+	if m.MinSpeed > 0 {
+		parts = append(parts, fmt.Sprintf(">= %d MT/s", m.MinSpeed))
+	}
+	s := strings.Join(parts, " ")
+	if m.PartNumber != "" {
+		s += " (e.g. " + m.PartNumber + ")"
+	}
+	return s
+}
+
+// ChannelPlan is a channel before and after the empty slots are filled
+type ChannelPlan struct {
+	Name            string `json:"name"`
+	Modules         int    `json:"modules"`
+	Capacity        uint64 `json:"capacity_mb"` // In MB
+	PlannedModules  int    `json:"planned_modules"`
+	PlannedCapacity uint64 `json:"planned_capacity_mb"` // In MB
+}
+
+// SocketPlan is the upgrade plan of one socket
+type SocketPlan struct {
+	Socket     string   `json:"socket"`
+	Slots      int      `json:"slots"`
+	EmptySlots []string `json:"empty_slots"`
+	Installed  uint64   `json:"installed_mb"` // In MB
+	// Maximum is the most memory the socket supports, from the maximum
+	// capacity of its arrays shared among their slots; 0 if unknown
+	Maximum uint64 `json:"maximum_mb"`
+	// Headroom is how much more memory the socket supports, which may need
+	// larger modules in place of the installed ones
+	Headroom uint64 `json:"headroom_mb"`
+	// Addable is the memory added by filling the empty slots with Module
+	Addable  uint64        `json:"addable_mb"`
+	Module   *Module       `json:"module,omitempty"` // Nil if no modules are installed
+	Channels []ChannelPlan `json:"channels,omitempty"`
+	// Balanced is true when the channels hold the same memory in the same
+	// number of modules once the empty slots are filled
+	Balanced bool     `json:"balanced"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// Plan is the memory upgrade plan of a system
+type Plan struct {
+	Installed uint64        `json:"installed_mb"` // In MB
+	Maximum   uint64        `json:"maximum_mb"`   // In MB, 0 if unknown
+	Addable   uint64        `json:"addable_mb"`   // In MB
+	Sockets   []*SocketPlan `json:"sockets"`
+}
+
+// Plan works out, for each socket, how much memory can still be added,
+// which slots are free, which modules fit alongside the installed ones and
+// how balanced the channels would be
+func (t *Topology) Plan() *Plan {
+	p := &Plan{}
+	all := populated(t.SystemDevices())
+	for _, s := range t.Sockets() {
+		sp := planSocket(s, all)
+		p.Sockets = append(p.Sockets, sp)
+		p.Installed += sp.Installed
+		p.Addable += sp.Addable
+	}
+	p.Maximum = t.maximum()
+	return p
+}
+
+// maximum returns the maximum capacity of the system memory arrays in MB,
+// or 0 if any is unknown
+func (t *Topology) maximum() uint64 {
+	var total uint64
+	arrays := t.systemArrays()
+	for _, a := range arrays {
+		mb := arrayMaximum(a)
+		if mb == 0 {
+			return 0
+		}
+		total += mb
+	}
+	return total
+}
+
+// arrayMaximum returns the maximum capacity of an array in MB, or 0 if it
+// is unknown
+func arrayMaximum(a *Array) uint64 {
+	if a.Info.MaximumCapacity == 0x80000000 {
+		// Extended Maximum Capacity not provided
+		return 0
+	}
+	return a.Info.MaximumCapacity / 1024
+}
+
+// arraySlots returns the number of slots of an array
+func arraySlots(a *Array) int {
+	if n := int(a.Info.NumberOfMemoryDevices); n > 0 {
+		return n
+	}
+	return len(a.Devices)
+}
+
+func planSocket(s *Socket, all []*Device) *SocketPlan {
+	sp := &SocketPlan{Socket: s.Name, Slots: len(s.Devices), EmptySlots: []string{}, Installed: s.Capacity()}
+	for _, d := range s.Devices {
+		if !d.Info.IsPopulated() {
+			sp.EmptySlots = append(sp.EmptySlots, d.Locator())
+		}
+	}
+
+	// The socket's share of the maximum capacity of its arrays
+	slotsIn := make(map[*Array]int)
+	known := true
+	for _, d := range s.Devices {
+		if d.Array == nil {
+			known = false
+			continue
+		}
+		slotsIn[d.Array]++
+	}
+	for a, n := range slotsIn {
+		mb := arrayMaximum(a)
+		if mb == 0 {
+			known = false
+			break
+		}
+		sp.Maximum += mb * uint64(n) / uint64(arraySlots(a))
+	}
+	if !known {
+		sp.Maximum = 0
+		sp.Notes = append(sp.Notes, "maximum capacity is not known")
+	} else if sp.Maximum > sp.Installed {
+		sp.Headroom = sp.Maximum - sp.Installed
+	}
+
+	installed := populated(s.Devices)
+	if len(installed) == 0 {
+		// Match the modules of the other sockets
+		installed = all
+	}
+	sp.Module = commonModule(installed)
+	if sp.Module == nil {
+		sp.Notes = append(sp.Notes, "no modules are installed to match")
+		return sp
+	}
+
+	empty := uint64(len(sp.EmptySlots))
+	if empty > 0 && sp.Maximum > 0 && sp.Installed+empty*sp.Module.Size > sp.Maximum {
+		// Fill the empty slots with the largest power of two that fits
+		size := uint64(1024)
+		for size*2*empty <= sp.Headroom {
+			size *= 2
+		}
+		if size*empty > sp.Headroom {
+			size = 0
+		}
+		sp.Notes = append(sp.Notes, fmt.Sprintf("%s modules in every empty slot exceed the maximum capacity", formatMB(sp.Module.Size)))
+		sp.Module.Size = size
+		sp.Module.PartNumber = ""
+	}
+	sp.Addable = empty * sp.Module.Size
+	if len(sp.EmptySlots) == 0 {
+		sp.Notes = append(sp.Notes, "all slots are populated; adding memory needs larger modules")
+	}
+
+	sp.Balanced = true
+	for _, c := range s.Channels {
+		cp := ChannelPlan{Name: c.Name, Modules: c.Populated(), Capacity: c.Capacity()}
+		cp.PlannedModules = len(c.Devices)
+		cp.PlannedCapacity = cp.Capacity + uint64(len(c.Devices)-cp.Modules)*sp.Module.Size
+		if len(sp.Channels) > 0 {
+			first := sp.Channels[0]
+			if cp.PlannedModules != first.PlannedModules || cp.PlannedCapacity != first.PlannedCapacity {
+				sp.Balanced = false
+			}
+		}
+		sp.Channels = append(sp.Channels, cp)
+	}
+	if len(s.Channels) == 0 {
+		sp.Balanced = false
+		sp.Notes = append(sp.Notes, "channels are not known, so balance cannot be checked")
+	}
+	return sp
+}
+
+// commonModule returns the module installed most often, or nil if none are
+func commonModule(devices []*Device) *Module {
+	counts := make(map[Module]int)
+	var best Module
+	for _, d := range devices {
+		m := moduleOf(d)
+		counts[m]++
+		if counts[m] > counts[best] {
+			best = m
+		}
+	}
+	if counts[best] == 0 {
+		return nil
+	}
+	return &best
+}
+
+// moduleOf describes an installed module
+func moduleOf(d *Device) Module {
+	m := Module{
+		Type:       d.Info.MemoryType,
+		FormFactor: d.Info.FormFactor,
+		Ranks:      d.Info.Ranks(),
+		Size:       d.Info.Size,
+		MinSpeed:   d.Info.GetConfiguredSpeed(),
+		PartNumber: gosmbios.Sanitize(d.Info.PartNumber),
+	}
+	m.ECC, _ = hasECC(d)
+	switch {
+	case d.Info.TypeDetail.Has(type17.TypeDetailLRDIMM):
+		m.Buffering = "LRDIMM"
+	case d.Info.TypeDetail.Has(type17.TypeDetailRegistered):
+		m.Buffering = "Registered"
+	case d.Info.TypeDetail.Has(type17.TypeDetailUnbuffered):
+		m.Buffering = "Unbuffered"
+	}
+	if m.MinSpeed == 0 {
+		m.MinSpeed = d.Info.GetSpeed()
+	}
+	return m
+}
+
+// WriteText writes the plan of each socket
+func (p *Plan) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, sp := range p.Sockets {
+		fmt.Fprintf(&b, "%s:\n", sp.Socket)
+		fmt.Fprintf(&b, "  Installed:   %s in %d of %d slot(s)\n", formatMB(sp.Installed), sp.Slots-len(sp.EmptySlots), sp.Slots)
+		if sp.Maximum > 0 {
+			fmt.Fprintf(&b, "  Maximum:     %s (%s free)\n", formatMB(sp.Maximum), formatMB(sp.Headroom))
+		} else {
+			fmt.Fprintf(&b, "  Maximum:     Unknown\n")
+		}
+		if len(sp.EmptySlots) > 0 {
+			fmt.Fprintf(&b, "  Empty Slots: %s\n", strings.Join(sp.EmptySlots, ", "))
+		}
+		if sp.Module != nil {
+			fmt.Fprintf(&b, "  Module:      %s\n", sp.Module)
+			fmt.Fprintf(&b, "  Addable:     %s\n", formatMB(sp.Addable))
+		}
+		for _, c := range sp.Channels {
+			fmt.Fprintf(&b, "  Channel %-4s %s in %d -> %s in %d\n", c.Name+":", formatMB(c.Capacity), c.Modules, formatMB(c.PlannedCapacity), c.PlannedModules)
+		}
+		if len(sp.Channels) > 0 {
+			if sp.Balanced {
+				fmt.Fprintf(&b, "  Balance:     balanced after upgrade\n")
+			} else {
+				fmt.Fprintf(&b, "  Balance:     unbalanced after upgrade\n")
+			}
+		}
+		for _, n := range sp.Notes {
+			fmt.Fprintf(&b, "  Note:        %s\n", n)
+		}
+	}
+	fmt.Fprintf(&b, "Total: %s installed, %s addable", formatMB(p.Installed), formatMB(p.Addable))
+	if p.Maximum > 0 {
+		fmt.Fprintf(&b, ", %s maximum", formatMB(p.Maximum))
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
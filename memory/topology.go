@@ -0,0 +1,306 @@
+// Package memory models the system memory described by SMBIOS: the Physical
+// Memory Arrays (Type 16), their Memory Devices (Type 17), and the address
+// ranges mapped to them (Types 19 and 20).
+//
+// A Topology answers which DIMM backs a physical address, such as one
+// reported in a machine-check or EDAC log. Type 19 ranges give the span of
+// each array and Type 20 ranges the span of each device, with its partition
+// row and interleave position. Devices in one partition row are accessed
+// together, so an address in their range is backed by the whole row. In an
+// interleave set every device reports the same range; SMBIOS does not record
+// the interleave granularity, so the device is only picked when it is set in
+// Topology.InterleaveGranularity, and all devices of the set are returned
+// otherwise. Many current systems omit Type 20, in which case an address
+// resolves to the populated devices of the array whose Type 19 range holds it.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type16"
+	"github.com/earentir/gosmbios/types/type17"
+	"github.com/earentir/gosmbios/types/type19"
+	"github.com/earentir/gosmbios/types/type20"
+)
+
+// ErrAddressNotMapped is returned when no memory range holds an address
+var ErrAddressNotMapped = errors.New("smbios: address is not mapped to memory")
+
+// Array is a Physical Memory Array and its devices and mapped ranges
+type Array struct {
+	Handle  uint16
+	Info    *type16.MemoryArray
+	Devices []*Device
+	Ranges  []*ArrayRange
+}
+
+// Device is a Memory Device and the ranges mapped to it
+type Device struct {
+	Handle   uint16
+	Info     *type17.MemoryDevice
+	Array    *Array // Nil if the array is not described
+	Ranges   []*DeviceRange
+	Position Position
+}
+
+// Locator returns the device and bank locators, e.g. "DIMM_A1 / BANK 0"
+func (d *Device) Locator() string {
+	if d.Info.BankLocator == "" {
+		return d.Info.DeviceLocator
+	}
+	return d.Info.DeviceLocator + " / " + d.Info.BankLocator
+}
+
+// ArrayRange is a physical address range mapped to an array (Type 19)
+type ArrayRange struct {
+	Handle         uint16
+	Start          uint64 // In bytes
+	End            uint64 // In bytes, inclusive
+	PartitionWidth uint8  // Number of devices that form one partition row
+	Array          *Array // Nil if the array is not described
+	Devices        []*DeviceRange
+}
+
+// Contains returns true if the range holds the address
+func (r *ArrayRange) Contains(addr uint64) bool {
+	return addr >= r.Start && addr <= r.End
+}
+
+// DeviceRange is a physical address range mapped to a device (Type 20)
+type DeviceRange struct {
+	Handle             uint16
+	Start              uint64      // In bytes
+	End                uint64      // In bytes, inclusive
+	Device             *Device     // Nil if the device is not described
+	ArrayRange         *ArrayRange // Nil if the array range is not described
+	PartitionRow       uint8       // 0 unknown, 0xFF not applicable
+	InterleavePosition uint8       // 0 not interleaved, 0xFF unknown
+	InterleaveDepth    uint8       // 0 not interleaved, 0xFF unknown
+}
+
+// Contains returns true if the range holds the address
+func (r *DeviceRange) Contains(addr uint64) bool {
+	return addr >= r.Start && addr <= r.End
+}
+
+// interleaved returns true if the range is part of an interleave set with
+// a known position
+func (r *DeviceRange) interleaved() bool {
+	return r.InterleavePosition != 0 && r.InterleavePosition != 0xFF
+}
+
+// Topology is the memory described by a table
+type Topology struct {
+	Arrays       []*Array
+	Devices      []*Device
+	ArrayRanges  []*ArrayRange
+	DeviceRanges []*DeviceRange
+
+	// InterleaveGranularity is the number of bytes accessed from one
+	// device before moving to the next in an interleave set, such as 64
+	// for cache-line interleaving. It is not recorded in SMBIOS; when zero,
+	// addresses in interleaved ranges resolve to the whole set.
+	InterleaveGranularity uint64
+}
+
+// Build builds the topology of a table
+func Build(sm *gosmbios.SMBIOS) (*Topology, error) {
+	devices, err := type17.GetAll(sm)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Topology{}
+	arrays := make(map[uint16]*Array)
+	if infos, err := type16.GetAll(sm); err == nil {
+		for _, info := range infos {
+			a := &Array{Handle: info.Header.Handle, Info: info}
+			arrays[a.Handle] = a
+			t.Arrays = append(t.Arrays, a)
+		}
+	}
+
+	byHandle := make(map[uint16]*Device)
+	for _, info := range devices {
+		d := &Device{Handle: info.Header.Handle, Info: info, Array: arrays[info.PhysicalMemoryArrayHandle]}
+		if d.Array != nil {
+			d.Array.Devices = append(d.Array.Devices, d)
+		}
+		byHandle[d.Handle] = d
+		t.Devices = append(t.Devices, d)
+	}
+	t.assignPositions(sm)
+
+	arrayRanges := make(map[uint16]*ArrayRange)
+	if infos, err := type19.GetAll(sm); err == nil {
+		for _, info := range infos {
+			r := &ArrayRange{
+				Handle:         info.Header.Handle,
+				Start:          info.GetStartingAddressBytes(),
+				End:            endAddress(info.GetEndingAddressBytes(), info.EndingAddress != 0xFFFFFFFF),
+				PartitionWidth: info.PartitionWidth,
+				Array:          arrays[info.MemoryArrayHandle],
+			}
+			if r.Array != nil {
+				r.Array.Ranges = append(r.Array.Ranges, r)
+			}
+			arrayRanges[r.Handle] = r
+			t.ArrayRanges = append(t.ArrayRanges, r)
+		}
+	}
+
+	if infos, err := type20.GetAll(sm); err == nil {
+		for _, info := range infos {
+			r := &DeviceRange{
+				Handle:             info.Header.Handle,
+				Start:              info.GetStartingAddressBytes(),
+				End:                endAddress(info.GetEndingAddressBytes(), info.EndingAddress != 0xFFFFFFFF),
+				Device:             byHandle[info.MemoryDeviceHandle],
+				ArrayRange:         arrayRanges[info.MemoryArrayMappedAddressHandle],
+				PartitionRow:       info.PartitionRowPosition,
+				InterleavePosition: info.InterleavePosition,
+				InterleaveDepth:    info.InterleavedDataDepth,
+			}
+			if r.Device != nil {
+				r.Device.Ranges = append(r.Device.Ranges, r)
+			}
+			if r.ArrayRange != nil {
+				r.ArrayRange.Devices = append(r.ArrayRange.Devices, r)
+			}
+			t.DeviceRanges = append(t.DeviceRanges, r)
+		}
+	}
+
+	return t, nil
+}
+
+// endAddress returns the inclusive end address in bytes. The legacy fields
+// hold the address of the last kilobyte, so the whole kilobyte is included.
+func endAddress(end uint64, kilobytes bool) uint64 {
+	if kilobytes {
+		return end + 1023
+	}
+	return end
+}
+
+// Resolution is the memory backing a physical address
+type Resolution struct {
+	Address    uint64
+	ArrayRange *ArrayRange // Nil if no Type 19 range holds the address
+	Array      *Array
+	Ranges     []*DeviceRange // The Type 20 ranges holding the address
+	Devices    []*Device      // The devices backing the address
+	// Exact is true when Devices are known to hold the address: a single
+	// device, or the devices of one partition row. It is false when several
+	// candidates remain, such as an interleave set without a granularity or
+	// an array without Type 20 ranges.
+	Exact bool
+}
+
+// Locators returns the locators of the backing devices
+func (r *Resolution) Locators() []string {
+	return locators(r.Devices)
+}
+
+func (r *Resolution) String() string {
+	if len(r.Devices) == 0 {
+		return fmt.Sprintf("0x%X: no memory device", r.Address)
+	}
+	s := fmt.Sprintf("0x%X: %s", r.Address, strings.Join(r.Locators(), ", "))
+	if !r.Exact {
+		s += " (one of)"
+	}
+	return s
+}
+
+// Resolve returns the memory devices backing a physical address
+func (t *Topology) Resolve(addr uint64) (*Resolution, error) {
+	res := &Resolution{Address: addr}
+	for _, r := range t.ArrayRanges {
+		if r.Contains(addr) {
+			res.ArrayRange = r
+			res.Array = r.Array
+			break
+		}
+	}
+	for _, r := range t.DeviceRanges {
+		if r.Contains(addr) && r.Device != nil {
+			res.Ranges = append(res.Ranges, r)
+		}
+	}
+
+	if len(res.Ranges) == 0 {
+		if res.Array == nil {
+			return nil, ErrAddressNotMapped
+		}
+		// No device ranges: any populated device of the array
+		for _, d := range res.Array.Devices {
+			if d.Info.IsPopulated() {
+				res.Devices = append(res.Devices, d)
+			}
+		}
+		res.Exact = len(res.Devices) == 1
+		return res, nil
+	}
+	if res.ArrayRange == nil {
+		res.ArrayRange = res.Ranges[0].ArrayRange
+		if res.ArrayRange != nil {
+			res.Array = res.ArrayRange.Array
+		}
+	}
+
+	ranges, exact := t.selectInterleave(addr, res.Ranges)
+	for _, r := range ranges {
+		res.Devices = append(res.Devices, r.Device)
+	}
+	res.Exact = exact
+	sort.Slice(res.Devices, func(i, j int) bool {
+		return res.Devices[i].Info.DeviceLocator < res.Devices[j].Info.DeviceLocator
+	})
+	return res, nil
+}
+
+// selectInterleave narrows the ranges holding an address to the interleave
+// position that holds it, and reports whether the result is exact
+func (t *Topology) selectInterleave(addr uint64, ranges []*DeviceRange) ([]*DeviceRange, bool) {
+	positions := make(map[uint8][]*DeviceRange)
+	unknown := false
+	for _, r := range ranges {
+		if r.interleaved() {
+			positions[r.InterleavePosition] = append(positions[r.InterleavePosition], r)
+		} else if r.InterleavePosition == 0xFF {
+			unknown = true
+		}
+	}
+	switch {
+	case unknown:
+		return ranges, len(ranges) == 1
+	case len(positions) == 0:
+		// Not interleaved: a single device or one partition row
+		return ranges, true
+	case len(positions) == 1:
+		for _, set := range positions {
+			return set, true
+		}
+	}
+	if t.InterleaveGranularity == 0 {
+		return ranges, false
+	}
+
+	// Each position holds depth consecutive units in turn
+	first := ranges[0]
+	depth := uint64(first.InterleaveDepth)
+	if depth == 0 || depth == 0xFF {
+		depth = 1
+	}
+	ways := uint64(len(positions))
+	position := uint8((addr-first.Start)/(t.InterleaveGranularity*depth)%ways) + 1
+	if set, ok := positions[position]; ok {
+		return set, true
+	}
+	return ranges, false
+}
@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/earentir/gosmbios"
+)
+
+const (
+	kb = uint64(1024)
+	gb = 1024 * 1024 * kb
+)
+
+// tableBuilder builds synthetic Type 16, 17, 19 and 20 structures
+type tableBuilder struct {
+	t          *testing.T
+	structures []gosmbios.Structure
+}
+
+func (b *tableBuilder) add(structType uint8, handle uint16, formatted []byte, strs []string) {
+	b.t.Helper()
+	s, err := gosmbios.NewStructure(structType, handle, formatted, strs)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	b.structures = append(b.structures, s)
+}
+
+// array adds a system memory Physical Memory Array
+func (b *tableBuilder) array(handle uint16, devices uint16) {
+	f := make([]byte, 0x17)
+	f[0x04] = 0x03 // System board
+	f[0x05] = 0x03 // System memory
+	f[0x06] = 0x03 // No error correction
+	binary.LittleEndian.PutUint32(f[0x07:], 0x80000000)
+	binary.LittleEndian.PutUint16(f[0x0B:], 0xFFFE)
+	binary.LittleEndian.PutUint16(f[0x0D:], devices)
+	binary.LittleEndian.PutUint64(f[0x0F:], 64*gb)
+	b.add(16, handle, f[4:], nil)
+}
+
+// device adds a DDR4 DIMM of size MB, where 0 is an empty slot
+func (b *tableBuilder) device(handle, array uint16, locator string, size uint16) {
+	f := make([]byte, 0x22)
+	binary.LittleEndian.PutUint16(f[0x04:], array)
+	binary.LittleEndian.PutUint16(f[0x08:], 64)
+	binary.LittleEndian.PutUint16(f[0x0A:], 64)
+	binary.LittleEndian.PutUint16(f[0x0C:], size)
+	f[0x0E] = 0x09 // DIMM
+	f[0x10] = 1
+	f[0x12] = 0x1A // DDR4
+	b.add(17, handle, f[4:], []string{locator})
+}
+
+// arrayRange adds a Type 19 range from start to end inclusive in bytes,
+// using the legacy kilobyte fields when they can hold it
+func (b *tableBuilder) arrayRange(handle, array uint16, start, end uint64, width uint8) {
+	f := make([]byte, 0x1F)
+	if end/kb < 0xFFFFFFFF {
+		binary.LittleEndian.PutUint32(f[0x04:], uint32(start/kb))
+		binary.LittleEndian.PutUint32(f[0x08:], uint32(end/kb))
+	} else {
+		binary.LittleEndian.PutUint32(f[0x04:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(f[0x08:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint64(f[0x0F:], start)
+		binary.LittleEndian.PutUint64(f[0x17:], end)
+	}
+	binary.LittleEndian.PutUint16(f[0x0C:], array)
+	f[0x0E] = width
+	b.add(19, handle, f[4:], nil)
+}
+
+// deviceRange adds a Type 20 range like arrayRange, with an interleave
+// position and depth
+func (b *tableBuilder) deviceRange(handle, device, arrayRange uint16, start, end uint64, position, depth uint8) {
+	f := make([]byte, 0x23)
+	if end/kb < 0xFFFFFFFF {
+		binary.LittleEndian.PutUint32(f[0x04:], uint32(start/kb))
+		binary.LittleEndian.PutUint32(f[0x08:], uint32(end/kb))
+	} else {
+		binary.LittleEndian.PutUint32(f[0x04:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(f[0x08:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint64(f[0x13:], start)
+		binary.LittleEndian.PutUint64(f[0x1B:], end)
+	}
+	binary.LittleEndian.PutUint16(f[0x0C:], device)
+	binary.LittleEndian.PutUint16(f[0x0E:], arrayRange)
+	f[0x10] = 1
+	f[0x11] = position
+	f[0x12] = depth
+	b.add(20, handle, f[4:], nil)
+}
+
+func (b *tableBuilder) topology() *Topology {
+	b.t.Helper()
+	topo, err := Build(&gosmbios.SMBIOS{Structures: b.structures})
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return topo
+}
+
+// resolveCase is an address and the locators expected to back it
+type resolveCase struct {
+	addr     uint64
+	locators []string // Nil if the address is not mapped
+	exact    bool
+}
+
+func checkResolve(t *testing.T, topo *Topology, tests []resolveCase) {
+	t.Helper()
+	for _, tt := range tests {
+		res, err := topo.Resolve(tt.addr)
+		if tt.locators == nil {
+			if !errors.Is(err, ErrAddressNotMapped) {
+				t.Errorf("Resolve(0x%X) = %v, %v, want ErrAddressNotMapped", tt.addr, res, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(0x%X): %v", tt.addr, err)
+			continue
+		}
+		if got := res.Locators(); !reflect.DeepEqual(got, tt.locators) || res.Exact != tt.exact {
+			t.Errorf("Resolve(0x%X) = %v (exact %v), want %v (exact %v)", tt.addr, got, res.Exact, tt.locators, tt.exact)
+		}
+	}
+}
+
+func TestResolveInterleave(t *testing.T) {
+	b := &tableBuilder{t: t}
+	b.array(0x1000, 2)
+	b.device(0x1100, 0x1000, "DIMM_A", 8192)
+	b.device(0x1101, 0x1000, "DIMM_B", 8192)
+	b.arrayRange(0x1300, 0x1000, 0, 16*gb-1, 2)
+	b.deviceRange(0x1400, 0x1100, 0x1300, 0, 16*gb-1, 1, 1)
+	b.deviceRange(0x1401, 0x1101, 0x1300, 0, 16*gb-1, 2, 1)
+	topo := b.topology()
+
+	// Without a granularity the whole interleave set is returned
+	checkResolve(t, topo, []resolveCase{
+		{0, []string{"DIMM_A", "DIMM_B"}, false},
+	})
+
+	topo.InterleaveGranularity = 64
+	checkResolve(t, topo, []resolveCase{
+		{0, []string{"DIMM_A"}, true},
+		{63, []string{"DIMM_A"}, true},
+		{64, []string{"DIMM_B"}, true},
+		{127, []string{"DIMM_B"}, true},
+		{128, []string{"DIMM_A"}, true},
+		{0x1000_0040, []string{"DIMM_B"}, true},
+		// The legacy end address is the last kilobyte, which is included
+		{16*gb - 64, []string{"DIMM_B"}, true},
+		{16*gb - 1, []string{"DIMM_B"}, true},
+		{16 * gb, nil, false},
+	})
+}
+
+func TestResolveInterleaveDepth(t *testing.T) {
+	b := &tableBuilder{t: t}
+	b.array(0x1000, 2)
+	b.device(0x1100, 0x1000, "DIMM_A", 8192)
+	b.device(0x1101, 0x1000, "DIMM_B", 8192)
+	b.arrayRange(0x1300, 0x1000, 0, 16*gb-1, 2)
+	b.deviceRange(0x1400, 0x1100, 0x1300, 0, 16*gb-1, 1, 2)
+	b.deviceRange(0x1401, 0x1101, 0x1300, 0, 16*gb-1, 2, 2)
+	topo := b.topology()
+	topo.InterleaveGranularity = 64
+
+	// Each device holds two consecutive 64-byte units in turn
+	checkResolve(t, topo, []resolveCase{
+		{0, []string{"DIMM_A"}, true},
+		{64, []string{"DIMM_A"}, true},
+		{128, []string{"DIMM_B"}, true},
+		{191, []string{"DIMM_B"}, true},
+		{256, []string{"DIMM_A"}, true},
+	})
+}
+
+func TestResolveExtendedAddresses(t *testing.T) {
+	b := &tableBuilder{t: t}
+	b.array(0x1000, 2)
+	b.device(0x1100, 0x1000, "DIMM_A", 4096)
+	b.device(0x1101, 0x1000, "DIMM_B", 4096)
+	// Ranges above the 4 TiB reach of the kilobyte fields use the
+	// extended byte addresses
+	base := 8192 * gb
+	b.arrayRange(0x1300, 0x1000, base, base+8*gb-1, 1)
+	b.deviceRange(0x1400, 0x1100, 0x1300, base, base+4*gb-1, 0, 0)
+	b.deviceRange(0x1401, 0x1101, 0x1300, base+4*gb, base+8*gb-1, 0, 0)
+	// A range between 4 GiB and 4 TiB in the kilobyte fields
+	b.arrayRange(0x1301, 0x1000, 4*gb, 8*gb-1, 1)
+	b.deviceRange(0x1402, 0x1101, 0x1301, 4*gb, 8*gb-1, 0, 0)
+	topo := b.topology()
+
+	if got := topo.ArrayRanges[0].End; got != base+8*gb-1 {
+		t.Errorf("extended end = 0x%X, want 0x%X", got, base+8*gb-1)
+	}
+	checkResolve(t, topo, []resolveCase{
+		{base, []string{"DIMM_A"}, true},
+		{base + 4*gb - 1, []string{"DIMM_A"}, true},
+		{base + 4*gb, []string{"DIMM_B"}, true},
+		// Extended end addresses are exact bytes, with nothing added
+		{base + 8*gb - 1, []string{"DIMM_B"}, true},
+		{base + 8*gb, nil, false},
+		{4*gb + 0x1234, []string{"DIMM_B"}, true},
+		{8*gb - 1, []string{"DIMM_B"}, true},
+		{base - 1, nil, false},
+	})
+}
+
+func TestResolveWithoutDeviceRanges(t *testing.T) {
+	b := &tableBuilder{t: t}
+	b.array(0x1000, 3)
+	b.device(0x1100, 0x1000, "DIMM_A", 8192)
+	b.device(0x1101, 0x1000, "DIMM_B", 8192)
+	b.device(0x1102, 0x1000, "DIMM_C", 0)
+	b.arrayRange(0x1300, 0x1000, 0, 16*gb-1, 2)
+	b.array(0x2000, 2)
+	b.device(0x2100, 0x2000, "DIMM_D", 8192)
+	b.device(0x2101, 0x2000, "DIMM_E", 0)
+	b.arrayRange(0x2300, 0x2000, 16*gb, 24*gb-1, 1)
+	topo := b.topology()
+
+	// Any populated device of the array holding the address; exact only
+	// when there is one
+	checkResolve(t, topo, []resolveCase{
+		{0x1000, []string{"DIMM_A", "DIMM_B"}, false},
+		{16*gb - 1, []string{"DIMM_A", "DIMM_B"}, false},
+		{16 * gb, []string{"DIMM_D"}, true},
+		{24*gb - 1, []string{"DIMM_D"}, true},
+		{24 * gb, nil, false},
+	})
+}
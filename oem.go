@@ -0,0 +1,152 @@
+package gosmbios
+
+import (
+	"strings"
+	"sync"
+)
+
+// OEMStructure is an OEM-specific structure (Type 128-255) annotated with
+// hints about the vendor that defined it
+type OEMStructure struct {
+	Structure
+	Vendor   string // Canonical vendor name inferred from Type 0/1, empty if unknown
+	TypeName string // Vendor-specific name of the structure type, empty if unknown
+}
+
+// oemVendorAliases maps lowercase substrings of BIOS vendor / system
+// manufacturer strings to canonical vendor names
+var oemVendorAliases = []struct {
+	match  string
+	vendor string
+}{
+	{"hewlett packard enterprise", "HPE"},
+	{"hpe", "HPE"},
+	{"hewlett-packard", "HP"},
+	{"hp", "HP"},
+	{"dell", "Dell"},
+	{"lenovo", "Lenovo"},
+	{"ibm", "IBM"},
+	{"acer", "Acer"},
+	{"supermicro", "Supermicro"},
+	{"fujitsu", "Fujitsu"},
+	{"cisco", "Cisco"},
+	{"intel", "Intel"},
+	{"asus", "ASUS"},
+	{"gigabyte", "Gigabyte"},
+	{"american megatrends", "AMI"},
+	{"insyde", "Insyde"},
+	{"phoenix", "Phoenix"},
+}
+
+// oemTypeNamesMu guards oemTypeNames
+var oemTypeNamesMu sync.RWMutex
+
+// oemTypeNames holds known vendor-specific structure type names, keyed by
+// canonical vendor name. Extend it with RegisterOEMType.
+var oemTypeNames = map[string]map[uint8]string{
+	"Dell": {
+		0xD0: "Revisions and IDs",
+		0xDA: "Calling Interface",
+	},
+	"HP": {
+		0xD1: "BIOS PXE NIC PCI and MAC Information",
+		0xD8: "Version Indicator",
+		0xEE: "USB Port Connector Correlation Record",
+	},
+	"HPE": {
+		0xD1: "BIOS PXE NIC PCI and MAC Information",
+		0xD8: "Version Indicator",
+		0xEE: "USB Port Connector Correlation Record",
+	},
+	"Lenovo": {
+		0x83: "ThinkVantage Technologies",
+		0x87: "TPM Device Capabilities",
+	},
+	"IBM": {
+		0x83: "ThinkVantage Technologies",
+	},
+	"Acer": {
+		0xAA: "Acer Hotkey Function",
+	},
+}
+
+// RegisterOEMType records the name of a vendor-specific structure type so it is
+// reported by OEMStructures. The vendor must be a canonical name as returned by
+// OEMVendor. Registering an existing entry replaces it.
+func RegisterOEMType(vendor string, structType uint8, name string) {
+	oemTypeNamesMu.Lock()
+	defer oemTypeNamesMu.Unlock()
+	if oemTypeNames[vendor] == nil {
+		oemTypeNames[vendor] = make(map[uint8]string)
+	}
+	oemTypeNames[vendor][structType] = name
+}
+
+// OEMTypeName returns the vendor-specific name of an OEM structure type,
+// or an empty string if it is not known
+func OEMTypeName(vendor string, structType uint8) string {
+	oemTypeNamesMu.RLock()
+	defer oemTypeNamesMu.RUnlock()
+	return oemTypeNames[vendor][structType]
+}
+
+// normalizeOEMVendor maps a free-form manufacturer string to a canonical vendor
+func normalizeOEMVendor(s string) string {
+	lower := strings.ToLower(strings.TrimSpace(s))
+	if lower == "" {
+		return ""
+	}
+	for _, alias := range oemVendorAliases {
+		// Short aliases must match a whole word to avoid false positives
+		if len(alias.match) <= 3 {
+			for _, word := range strings.FieldsFunc(lower, func(r rune) bool {
+				return r == ' ' || r == ',' || r == '.' || r == '-'
+			}) {
+				if word == alias.match {
+					return alias.vendor
+				}
+			}
+			continue
+		}
+		if strings.Contains(lower, alias.match) {
+			return alias.vendor
+		}
+	}
+	return ""
+}
+
+// OEMVendor returns the canonical vendor that owns the OEM structure types in
+// this table. The system manufacturer (Type 1) is preferred over the BIOS
+// vendor (Type 0), because OEM types are defined by the platform vendor.
+func (sm *SMBIOS) OEMVendor() string {
+	if s := sm.GetStructure(1); s != nil {
+		if v := normalizeOEMVendor(s.GetString(s.GetByte(0x04))); v != "" {
+			return v
+		}
+	}
+	if s := sm.GetStructure(0); s != nil {
+		if v := normalizeOEMVendor(s.GetString(s.GetByte(0x04))); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// OEMStructures returns all OEM-specific structures (Type 128-255) in table
+// order, annotated with vendor hints
+func (sm *SMBIOS) OEMStructures() []OEMStructure {
+	vendor := sm.OEMVendor()
+
+	var result []OEMStructure
+	for _, s := range sm.Structures {
+		if !s.IsOEM() {
+			continue
+		}
+		result = append(result, OEMStructure{
+			Structure: s,
+			Vendor:    vendor,
+			TypeName:  OEMTypeName(vendor, s.Header.Type),
+		})
+	}
+	return result
+}
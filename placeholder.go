@@ -0,0 +1,187 @@
+package gosmbios
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultPlaceholders are firmware default values seen in identity fields.
+// Entries are compared after normalization (lowercase, trimmed, single spaces).
+var defaultPlaceholders = []string{
+	"default string",
+	"default",
+	"to be filled by o.e.m.",
+	"to be filled by o.e.m",
+	"to be filled by oem",
+	"filled by o.e.m.",
+	"fill by oem",
+	"o.e.m.",
+	"oem",
+	"system serial number",
+	"system product name",
+	"system manufacturer",
+	"system version",
+	"system sku",
+	"system sku number",
+	"sku",
+	"base board serial number",
+	"base board product name",
+	"base board manufacturer",
+	"baseboard serial number",
+	"board serial number",
+	"chassis serial number",
+	"chassis manufacture",
+	"chassis manufacturer",
+	"chassis version",
+	"chassis asset tag",
+	"oem chassis manufacturer",
+	"type2 - board serial number",
+	"type2 - board manufacturer",
+	"type2 - board product name",
+	"type2 - board version",
+	"type2 - board asset tag",
+	"type1productconfigid",
+	"serial number",
+	"asset tag",
+	"asset-1234567890",
+	"no asset tag",
+	"no asset information",
+	"not specified",
+	"not applicable",
+	"not available",
+	"not present",
+	"not defined",
+	"n/a",
+	"na",
+	"none",
+	"null",
+	"empty",
+	"invalid",
+	"unknown",
+	"unknow",
+	"0",
+	"00",
+	"000",
+	"-",
+	".",
+	"x",
+	"xx",
+	"xxx",
+}
+
+// defaultPlaceholderPatterns match generated defaults such as "SerNum0" or
+// "PartNum3" on Type 17
+var defaultPlaceholderPatterns = []string{
+	`^(sernum|partnum|assettagnum|assettag|manufacturer|modulemanufacturer)\d*$`,
+	`^(dimm|memory) ?(serial|part) ?(number|num)?$`,
+}
+
+var (
+	placeholderMu       sync.RWMutex
+	placeholderValues   = make(map[string]bool)
+	placeholderPatterns []*regexp.Regexp
+)
+
+func init() {
+	RegisterPlaceholder(defaultPlaceholders...)
+	for _, p := range defaultPlaceholderPatterns {
+		placeholderPatterns = append(placeholderPatterns, regexp.MustCompile(p))
+	}
+}
+
+// normalizePlaceholder lowercases, trims and collapses white space
+func normalizePlaceholder(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// RegisterPlaceholder adds values that should be treated as placeholders.
+// Matching is case-insensitive and ignores surrounding and repeated white space.
+func RegisterPlaceholder(values ...string) {
+	placeholderMu.Lock()
+	defer placeholderMu.Unlock()
+	for _, v := range values {
+		placeholderValues[normalizePlaceholder(v)] = true
+	}
+}
+
+// RegisterPlaceholderPattern adds a regular expression that marks matching
+// values as placeholders. It is applied to the normalized (lowercase) value.
+func RegisterPlaceholderPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	placeholderMu.Lock()
+	defer placeholderMu.Unlock()
+	placeholderPatterns = append(placeholderPatterns, re)
+	return nil
+}
+
+// isRepeatedChar reports whether s is at least 4 copies of one character,
+// e.g. "00000000", "FFFFFFFF" or "........"
+func isRepeatedChar(s string) bool {
+	if len(s) < 4 {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigitRun reports whether s is a run of at least 6 ascending consecutive
+// digits starting at 0 or 1, e.g. "0123456789" or "123456"
+func isDigitRun(s string) bool {
+	if len(s) < 6 || (s[0] != '0' && s[0] != '1') {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[i-1]+1 && !(s[i-1] == '9' && s[i] == '0') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPlaceholder returns true if s is a known firmware default or junk value
+// such as "Default string", "To Be Filled By O.E.M." or "0123456789".
+// Empty strings are not placeholders; use IsMeaningful to reject both.
+func IsPlaceholder(s string) bool {
+	n := normalizePlaceholder(s)
+	if n == "" {
+		return false
+	}
+	if isRepeatedChar(n) || isDigitRun(n) {
+		return true
+	}
+
+	placeholderMu.RLock()
+	defer placeholderMu.RUnlock()
+	if placeholderValues[n] {
+		return true
+	}
+	for _, re := range placeholderPatterns {
+		if re.MatchString(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMeaningful returns true if s carries real information: it is neither
+// empty, white space only, nor a placeholder
+func IsMeaningful(s string) bool {
+	return strings.TrimSpace(s) != "" && !IsPlaceholder(s)
+}
+
+// Sanitize returns s with surrounding white space removed, or an empty
+// string if s is not meaningful
+func Sanitize(s string) string {
+	if !IsMeaningful(s) {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,576 @@
+package policy
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/decode"
+	"github.com/earentir/gosmbios/types"
+)
+
+// Expr is a compiled policy expression
+type Expr struct {
+	src  string
+	root node
+}
+
+// String returns the expression source
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression against a table and returns the outcome
+// together with the values it was based on
+func (e *Expr) Eval(sm *gosmbios.SMBIOS) (bool, []string, error) {
+	ctx := &evalContext{sm: sm, decoded: map[uint8][]any{}}
+	v, err := e.root.eval(ctx)
+	if err != nil {
+		return false, ctx.evidence, err
+	}
+	return v.truthy(), ctx.evidence, nil
+}
+
+// evalContext holds the table and the evidence gathered during evaluation
+type evalContext struct {
+	sm       *gosmbios.SMBIOS
+	decoded  map[uint8][]any
+	evidence []string
+}
+
+// structures returns the decoded structures of a type
+func (ctx *evalContext) structures(structType uint8) ([]any, error) {
+	if list, ok := ctx.decoded[structType]; ok {
+		return list, nil
+	}
+	var list []any
+	for _, s := range ctx.sm.GetStructures(structType) {
+		s := s
+		v, err := decode.Structure(&s)
+		if errors.Is(err, decode.ErrNoDecoder) {
+			return nil, fmt.Errorf("type %d has no decoder, so its fields cannot be read", structType)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("type %d at handle 0x%04X: %w", structType, s.Header.Handle, err)
+		}
+		list = append(list, v)
+	}
+	ctx.decoded[structType] = list
+	return list, nil
+}
+
+// note records a piece of evidence once
+func (ctx *evalContext) note(s string) {
+	for _, e := range ctx.evidence {
+		if e == s {
+			return
+		}
+	}
+	ctx.evidence = append(ctx.evidence, s)
+}
+
+// valueKind is the kind of an evaluated value
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindBool
+	kindList
+)
+
+// value is the result of evaluating a node. Scalars from decoded fields keep
+// both their number and their String() form, so an enum can be compared
+// with either 3 or Enabled.
+type value struct {
+	kind  valueKind
+	str   string
+	num   float64
+	isNum bool
+	b     bool
+	list  []value
+}
+
+func strValue(s string) value   { return value{kind: kindString, str: s} }
+func boolValue(b bool) value    { return value{kind: kindBool, b: b, str: strconv.FormatBool(b)} }
+func listValue(l []value) value { return value{kind: kindList, list: l} }
+func numValue(n float64) value {
+	return value{kind: kindNumber, num: n, isNum: true, str: strconv.FormatFloat(n, 'f', -1, 64)}
+}
+
+// String returns the value as shown in evidence
+func (v value) String() string {
+	switch v.kind {
+	case kindList:
+		var parts []string
+		for _, e := range v.list {
+			parts = append(parts, e.String())
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case kindString:
+		return strconv.Quote(v.str)
+	default:
+		return v.str
+	}
+}
+
+// elems returns the elements of a list, or the value itself for a scalar
+func (v value) elems() []value {
+	if v.kind == kindList {
+		return v.list
+	}
+	return []value{v}
+}
+
+// number returns the numeric form of a value
+func (v value) number() (float64, bool) {
+	switch {
+	case v.isNum:
+		return v.num, true
+	case v.kind == kindBool:
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+	return n, err == nil
+}
+
+// truthy returns the boolean meaning of a value. A list is true if it is
+// non-empty and all its elements are true.
+func (v value) truthy() bool {
+	switch v.kind {
+	case kindBool:
+		return v.b
+	case kindNumber:
+		return v.num != 0
+	case kindList:
+		if len(v.list) == 0 {
+			return false
+		}
+		for _, e := range v.list {
+			if !e.truthy() {
+				return false
+			}
+		}
+		return true
+	default:
+		return v.str != ""
+	}
+}
+
+func (n *literalNode) eval(*evalContext) (value, error) {
+	return n.v, nil
+}
+
+func (n *notNode) eval(ctx *evalContext) (value, error) {
+	v, err := n.x.eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+	return boolValue(!v.truthy()), nil
+}
+
+func (n *binaryNode) eval(ctx *evalContext) (value, error) {
+	l, err := n.l.eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+	switch n.op {
+	case "and":
+		if !l.truthy() {
+			return boolValue(false), nil
+		}
+		r, err := n.r.eval(ctx)
+		if err != nil {
+			return value{}, err
+		}
+		return boolValue(r.truthy()), nil
+	case "or":
+		if l.truthy() {
+			return boolValue(true), nil
+		}
+		r, err := n.r.eval(ctx)
+		if err != nil {
+			return value{}, err
+		}
+		return boolValue(r.truthy()), nil
+	}
+
+	r, err := n.r.eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+
+	// Every value on the left must satisfy the comparison with some value
+	// on the right, and there must be at least one
+	lefts, rights := l.elems(), r.elems()
+	if len(lefts) == 0 || len(rights) == 0 {
+		return boolValue(false), nil
+	}
+	for _, a := range lefts {
+		ok := false
+		for _, b := range rights {
+			match, err := compare(n.op, a, b)
+			if err != nil {
+				return value{}, err
+			}
+			if match {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return boolValue(false), nil
+		}
+	}
+	return boolValue(true), nil
+}
+
+// dateLayouts are the accepted date formats; SMBIOS uses mm/dd/yyyy
+var dateLayouts = []string{"01/02/2006", "2006-01-02", "01/02/06", "2006/01/02"}
+
+// parseDate parses a date in any of the accepted formats
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compare compares two scalars: as numbers if both are numeric, as dates if
+// both are dates, and otherwise as case-insensitive strings
+func compare(op string, a, b value) (bool, error) {
+	switch op {
+	case "contains":
+		return strings.Contains(strings.ToLower(a.str), strings.ToLower(b.str)), nil
+	case "matches":
+		re, err := regexp.Compile(b.str)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(a.str), nil
+	}
+
+	var c int
+	if a.kind == kindBool || b.kind == kindBool {
+		if op != "==" && op != "!=" {
+			return false, fmt.Errorf("cannot order booleans with %s", op)
+		}
+		if a.truthy() == b.truthy() {
+			c = 0
+		} else {
+			c = 1
+		}
+	} else if x, ok := a.number(); ok {
+		y, ok2 := b.number()
+		if !ok2 {
+			c = strings.Compare(strings.ToLower(strings.TrimSpace(a.str)), strings.ToLower(strings.TrimSpace(b.str)))
+		} else {
+			c = cmpFloat(x, y)
+		}
+	} else if x, ok := parseDate(a.str); ok {
+		if y, ok2 := parseDate(b.str); ok2 {
+			c = x.Compare(y)
+		} else {
+			c = strings.Compare(strings.ToLower(strings.TrimSpace(a.str)), strings.ToLower(strings.TrimSpace(b.str)))
+		}
+	} else {
+		c = strings.Compare(strings.ToLower(strings.TrimSpace(a.str)), strings.ToLower(strings.TrimSpace(b.str)))
+	}
+
+	switch op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %s", op)
+}
+
+func cmpFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func (n *pathNode) eval(ctx *evalContext) (value, error) {
+	if len(n.fields) == 0 {
+		// A bare typeN stands for the structures themselves, which need no
+		// decoder, so present(type200) works for OEM types
+		var out []value
+		for range ctx.sm.GetStructures(n.structType) {
+			out = append(out, value{kind: kindBool, b: true, str: types.TypeName(n.structType)})
+		}
+		v := listValue(out)
+		if len(out) == 0 {
+			ctx.note(fmt.Sprintf("%s: no type %d structures", n.text, n.structType))
+		} else {
+			ctx.note(n.text + " = " + v.String())
+		}
+		return v, nil
+	}
+
+	list, err := ctx.structures(n.structType)
+	if err != nil {
+		return value{}, err
+	}
+
+	var out []value
+	for _, decoded := range list {
+		vals := []reflect.Value{reflect.ValueOf(decoded)}
+		for _, name := range n.fields {
+			var next []reflect.Value
+			for _, v := range vals {
+				found, ok := lookup(v, name)
+				if !ok {
+					return value{}, fmt.Errorf("%s: no field or method %q", n.text, name)
+				}
+				next = append(next, found...)
+			}
+			vals = next
+		}
+		for _, v := range vals {
+			out = append(out, scalars(v)...)
+		}
+	}
+
+	v := listValue(out)
+	if len(list) == 0 {
+		ctx.note(fmt.Sprintf("%s: no type %d structures", n.text, n.structType))
+	} else {
+		ctx.note(n.text + " = " + v.String())
+	}
+	return v, nil
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	headerType   = reflect.TypeOf(gosmbios.Header{})
+)
+
+// lookup resolves a field or method name on a value. Slices are expanded;
+// a name not found on a struct is also looked up on its direct fields, so
+// type43.IsTPM2_0 finds Characteristics.IsTPM2_0.
+func lookup(v reflect.Value, name string) ([]reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, true
+		}
+		if m, ok := method(v, name); ok {
+			return []reflect.Value{m}, true
+		}
+		v = v.Elem()
+	}
+
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		var out []reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			found, ok := lookup(v.Index(i), name)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, found...)
+		}
+		return out, true
+	}
+
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+			return []reflect.Value{f}, true
+		}
+	}
+	if m, ok := method(v, name); ok {
+		return []reflect.Value{m}, true
+	}
+
+	if v.Kind() == reflect.Struct {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Type == headerType {
+				continue
+			}
+			f := v.Field(i)
+			if f.Kind() == reflect.Struct {
+				if inner := f.FieldByName(name); inner.IsValid() && inner.CanInterface() {
+					return []reflect.Value{inner}, true
+				}
+			}
+			if m, ok := method(f, name); ok {
+				return []reflect.Value{m}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// method calls a method taking no arguments and returning one value,
+// trying the pointer receiver first
+func method(v reflect.Value, name string) (reflect.Value, bool) {
+	candidates := []reflect.Value{v}
+	if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		candidates = []reflect.Value{p, v}
+	}
+	for _, c := range candidates {
+		m := c.MethodByName(name)
+		if !m.IsValid() {
+			continue
+		}
+		if m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			return reflect.Value{}, false
+		}
+		return m.Call(nil)[0], true
+	}
+	return reflect.Value{}, false
+}
+
+// scalars converts a decoded value into policy values
+func scalars(v reflect.Value) []value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var out value
+	switch v.Kind() {
+	case reflect.Bool:
+		out = boolValue(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out = numValue(float64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		out = numValue(float64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) {
+			return nil
+		}
+		out = numValue(f)
+	case reflect.String:
+		out = strValue(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			for i := range b {
+				b[i] = uint8(v.Index(i).Uint())
+			}
+			out = strValue(hex.EncodeToString(b))
+			break
+		}
+		var list []value
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, scalars(v.Index(i))...)
+		}
+		return list
+	default:
+		out = strValue(fmt.Sprint(v.Interface()))
+	}
+
+	// Enums and flags compare by name as well as by number
+	if v.Type().Implements(stringerType) {
+		out.str = v.Interface().(fmt.Stringer).String()
+		if out.kind == kindNumber {
+			out.kind = kindString
+		}
+	} else if reflect.PointerTo(v.Type()).Implements(stringerType) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		out.str = p.Interface().(fmt.Stringer).String()
+		if out.kind == kindNumber {
+			out.kind = kindString
+		}
+	}
+	return []value{out}
+}
+
+// functions maps function names to their number of arguments
+var functions = map[string]int{
+	"present": 1,
+	"count":   1,
+	"sum":     1,
+	"min":     1,
+	"max":     1,
+	"any":     1,
+	"all":     1,
+}
+
+func (n *callNode) eval(ctx *evalContext) (value, error) {
+	arg, err := n.args[0].eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+	elems := arg.elems()
+
+	var result value
+	switch n.name {
+	case "present":
+		result = boolValue(len(elems) > 0)
+	case "count":
+		result = numValue(float64(len(elems)))
+	case "any":
+		result = boolValue(false)
+		for _, e := range elems {
+			if e.truthy() {
+				result = boolValue(true)
+				break
+			}
+		}
+	case "all":
+		result = boolValue(listValue(elems).truthy())
+	case "sum", "min", "max":
+		var nums []float64
+		for _, e := range elems {
+			x, ok := e.number()
+			if !ok {
+				return value{}, fmt.Errorf("%s: %s is not a number", n, e)
+			}
+			nums = append(nums, x)
+		}
+		if len(nums) == 0 {
+			if n.name != "sum" {
+				// No values: the comparison fails rather than using 0
+				ctx.note(n.String() + ": no values")
+				return listValue(nil), nil
+			}
+			nums = []float64{0}
+		}
+		acc := nums[0]
+		for _, x := range nums[1:] {
+			switch n.name {
+			case "sum":
+				acc += x
+			case "min":
+				acc = math.Min(acc, x)
+			case "max":
+				acc = math.Max(acc, x)
+			}
+		}
+		result = numValue(acc)
+	}
+	ctx.note(n.String() + " = " + result.String())
+	return result, nil
+}
@@ -0,0 +1,404 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression syntax
+//
+//	expr     = or
+//	or       = and { ("or" | "||") and }
+//	and      = not { ("and" | "&&") not }
+//	not      = ("not" | "!") not | compare
+//	compare  = operand [ op operand ]
+//	op       = "==" | "!=" | "<" | "<=" | ">" | ">=" | "contains" | "matches"
+//	operand  = number | string | "true" | "false" | path | call | word | "(" expr ")"
+//	call     = name "(" [ expr { "," expr } ] ")"
+//	path     = "type" N { "." name }
+//
+// Numbers may carry a size suffix (MB, GB, TB), which is converted to
+// megabytes, the unit of Type 17 Size. Words that are not paths or keywords,
+// such as Enabled, are string literals.
+
+// tokenKind classifies a token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is a lexical token
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// sizeUnits are the number suffixes, in megabytes
+var sizeUnits = map[string]float64{
+	"MB": 1,
+	"GB": 1024,
+	"TB": 1024 * 1024,
+}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			var b strings.Builder
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				b.WriteByte(src[j])
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokString, text: b.String(), pos: i})
+			i = j + 1
+
+		case strings.ContainsRune("=!<>&|", rune(c)):
+			op := string(c)
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			if op == "=" || op == "&" || op == "|" {
+				return nil, fmt.Errorf("unexpected %q at %d", op, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+
+		case c >= '0' && c <= '9':
+			j := i
+			if strings.HasPrefix(src[i:], "0x") || strings.HasPrefix(src[i:], "0X") {
+				j += 2
+				for j < len(src) && isHex(src[j]) {
+					j++
+				}
+				n, err := strconv.ParseUint(src[i+2:j], 16, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid number %q at %d", src[i:j], i)
+				}
+				tokens = append(tokens, token{kind: tokNumber, text: src[i:j], num: float64(n), pos: i})
+				i = j
+				continue
+			}
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", src[i:j], i)
+			}
+			k := j
+			for k < len(src) && unicode.IsLetter(rune(src[k])) {
+				k++
+			}
+			if k > j {
+				unit, ok := sizeUnits[strings.ToUpper(src[j:k])]
+				if !ok {
+					return nil, fmt.Errorf("unknown unit %q at %d", src[j:k], j)
+				}
+				n *= unit
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[i:k], num: n, pos: i})
+			i = k
+
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			word := src[i:j]
+			switch strings.ToLower(word) {
+			case "and", "or", "not", "contains", "matches":
+				tokens = append(tokens, token{kind: tokOp, text: strings.ToLower(word), pos: i})
+			default:
+				tokens = append(tokens, token{kind: tokIdent, text: word, pos: i})
+			}
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, pos: len(src)})
+	return tokens, nil
+}
+
+// isHex reports whether c is a hexadecimal digit
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// node is a parsed expression
+type node interface {
+	eval(ctx *evalContext) (value, error)
+	String() string
+}
+
+type (
+	literalNode struct{ v value }
+	pathNode    struct {
+		structType uint8
+		fields     []string
+		text       string
+	}
+	callNode struct {
+		name string
+		args []node
+	}
+	notNode    struct{ x node }
+	binaryNode struct {
+		op   string
+		l, r node
+	}
+)
+
+func (n *literalNode) String() string { return n.v.String() }
+func (n *pathNode) String() string    { return n.text }
+func (n *notNode) String() string     { return "not " + n.x.String() }
+func (n *binaryNode) String() string  { return n.l.String() + " " + n.op + " " + n.r.String() }
+func (n *callNode) String() string {
+	var args []string
+	for _, a := range n.args {
+		args = append(args, a.String())
+	}
+	return n.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// typePath matches the start of a path, e.g. "type17"
+var typePath = regexp.MustCompile(`^(?i)type(\d{1,3})$`)
+
+// parser is a recursive descent parser over tokens
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Compile parses an expression
+func Compile(src string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+	return &Expr{src: src, root: n}, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isOp reports whether the next token is one of the operators
+func (p *parser) isOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) or() (node, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.isOp("or", "||"); !ok {
+			return l, nil
+		}
+		p.next()
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: "or", l: l, r: r}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.isOp("and", "&&"); !ok {
+			return l, nil
+		}
+		p.next()
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: "and", l: l, r: r}
+	}
+}
+
+func (p *parser) not() (node, error) {
+	if _, ok := p.isOp("not", "!"); ok {
+		p.next()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.compare()
+}
+
+func (p *parser) compare() (node, error) {
+	l, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.isOp("==", "!=", "<", "<=", ">", ">=", "contains", "matches")
+	if !ok {
+		return l, nil
+	}
+	p.next()
+	r, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if op == "matches" {
+		lit, ok := r.(*literalNode)
+		if !ok || lit.v.kind != kindString {
+			return nil, fmt.Errorf("matches needs a string pattern")
+		}
+		if _, err := regexp.Compile(lit.v.str); err != nil {
+			return nil, err
+		}
+	}
+	return &binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *parser) operand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literalNode{v: numValue(t.num)}, nil
+	case tokString:
+		return &literalNode{v: strValue(t.text)}, nil
+	case tokLParen:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, fmt.Errorf("missing ) for ( at %d", t.pos)
+		}
+		return n, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.call(t)
+		}
+		switch strings.ToLower(t.text) {
+		case "true":
+			return &literalNode{v: boolValue(true)}, nil
+		case "false":
+			return &literalNode{v: boolValue(false)}, nil
+		}
+		parts := strings.Split(t.text, ".")
+		if m := typePath.FindStringSubmatch(parts[0]); m != nil {
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n > 255 {
+				return nil, fmt.Errorf("invalid structure type %q at %d", parts[0], t.pos)
+			}
+			for _, f := range parts[1:] {
+				if f == "" {
+					return nil, fmt.Errorf("invalid path %q at %d", t.text, t.pos)
+				}
+			}
+			return &pathNode{structType: uint8(n), fields: parts[1:], text: t.text}, nil
+		}
+		// Any other word is a string, e.g. Enabled
+		return &literalNode{v: strValue(t.text)}, nil
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+}
+
+func (p *parser) call(name token) (node, error) {
+	fn := strings.ToLower(name.text)
+	arity, ok := functions[fn]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at %d", name.text, name.pos)
+	}
+	p.next() // (
+	c := &callNode{name: fn}
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.next().kind != tokRParen {
+		return nil, fmt.Errorf("missing ) in call to %s", fn)
+	}
+	if len(c.args) != arity {
+		return nil, fmt.Errorf("%s takes %d argument(s)", fn, arity)
+	}
+	return c, nil
+}
@@ -0,0 +1,240 @@
+// Package policy evaluates declarative hardware compliance rules against
+// SMBIOS tables, such as "TPM 2.0 present", "UEFI firmware" or "at least
+// 64GB of memory".
+//
+// Rules are expressions over decoded fields. A path such as type17.Size
+// yields the field from every structure of that type, and a comparison on it
+// holds only if there is at least one value and all of them satisfy it:
+//
+//	present(type43) and type43.IsTPM2_0
+//	type0.ReleaseDate > "2022-01-01"
+//	type0.IsUEFI
+//	type24.AdministratorPasswordStatus == Enabled
+//	sum(type17.Size) >= 64GB
+//
+// Path segments may name struct fields or methods taking no arguments; a
+// name not found on the structure is also looked up on its direct fields.
+// Each result lists the values the rule was decided on as evidence.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+)
+
+// Rule is one named compliance check. It is either an expression or the
+// structured form Type, Field, Op and Value, which is equivalent to the
+// expression "typeN.Field Op Value".
+type Rule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Expr        string `json:"expr,omitempty"`
+
+	Type  *uint8 `json:"type,omitempty"`
+	Field string `json:"field,omitempty"`
+	Op    string `json:"op,omitempty"`
+	Value any    `json:"value,omitempty"`
+
+	compiled *Expr
+}
+
+// Policy is a set of rules
+type Policy struct {
+	Name  string `json:"name,omitempty"`
+	Rules []Rule `json:"rules"`
+}
+
+// ErrInvalidPolicy is returned for rules that do not compile
+var ErrInvalidPolicy = errors.New("smbios: invalid policy")
+
+// ParsePolicy reads and compiles a JSON policy
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if err := p.Compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Compile compiles every rule, for policies built in code or embedded in
+// other configuration
+func (p *Policy) Compile() error {
+	for i := range p.Rules {
+		if err := p.Rules[i].compile(); err != nil {
+			return fmt.Errorf("%w: rule %d (%s): %v", ErrInvalidPolicy, i, p.Rules[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// LoadPolicy reads and compiles a JSON policy from a file
+func LoadPolicy(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// NewRule returns a compiled rule for an expression
+func NewRule(name, expr string) (*Rule, error) {
+	r := &Rule{Name: name, Expr: expr}
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return r, nil
+}
+
+// compile builds the expression of a rule
+func (r *Rule) compile() error {
+	if r.Expr == "" {
+		if r.Type == nil || r.Field == "" {
+			return errors.New("rule needs expr, or type and field")
+		}
+		r.Expr = fmt.Sprintf("type%d.%s", *r.Type, r.Field)
+		if r.Op != "" {
+			r.Expr += " " + r.Op + " " + literal(r.Value)
+		}
+	}
+	e, err := Compile(r.Expr)
+	if err != nil {
+		return err
+	}
+	r.compiled = e
+	if r.Name == "" {
+		r.Name = r.Expr
+	}
+	return nil
+}
+
+// literal renders a structured rule value as an expression operand
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return `""`
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		// Sizes such as "64GB" stay numbers
+		if tokens, err := lex(v); err == nil && len(tokens) == 2 && tokens[0].kind == tokNumber {
+			return v
+		}
+		return strconv.Quote(v)
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
+
+// RuleResult is the outcome of one rule
+type RuleResult struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Expr        string   `json:"expr"`
+	Passed      bool     `json:"passed"`
+	Error       string   `json:"error,omitempty"`
+	Evidence    []string `json:"evidence"`
+}
+
+// Result is the outcome of evaluating a policy against one table
+type Result struct {
+	Source string       `json:"source,omitempty"`
+	Policy string       `json:"policy,omitempty"`
+	Rules  []RuleResult `json:"rules"`
+}
+
+// Passed returns true if every rule passed
+func (r *Result) Passed() bool {
+	for _, rule := range r.Rules {
+		if !rule.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the number of rules that did not pass
+func (r *Result) Failed() int {
+	n := 0
+	for _, rule := range r.Rules {
+		if !rule.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate evaluates a rule against a table. A rule that cannot be evaluated,
+// for example because a field does not exist, fails with an error.
+func (r *Rule) Evaluate(sm *gosmbios.SMBIOS) RuleResult {
+	res := RuleResult{Name: r.Name, Description: r.Description, Expr: r.Expr, Evidence: []string{}}
+	if r.compiled == nil {
+		if err := r.compile(); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+		res.Name, res.Expr = r.Name, r.Expr
+	}
+	passed, evidence, err := r.compiled.Eval(sm)
+	res.Passed = passed
+	if evidence != nil {
+		res.Evidence = evidence
+	}
+	if err != nil {
+		res.Passed = false
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Evaluate evaluates every rule of the policy against a table
+func (p *Policy) Evaluate(sm *gosmbios.SMBIOS) *Result {
+	r := &Result{Policy: p.Name, Rules: []RuleResult{}}
+	for i := range p.Rules {
+		r.Rules = append(r.Rules, p.Rules[i].Evaluate(sm))
+	}
+	return r
+}
+
+// WriteText writes one PASS/FAIL line per rule with its evidence, followed
+// by a summary
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if r.Source != "" {
+		fmt.Fprintf(&b, "%s:\n", r.Source)
+	}
+	for _, rule := range r.Rules {
+		status := "PASS"
+		if !rule.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "  [%s] %s\n", status, rule.Name)
+		if rule.Name != rule.Expr {
+			fmt.Fprintf(&b, "         %s\n", rule.Expr)
+		}
+		if rule.Error != "" {
+			fmt.Fprintf(&b, "         error: %s\n", rule.Error)
+		}
+		for _, e := range rule.Evidence {
+			fmt.Fprintf(&b, "         - %s\n", e)
+		}
+	}
+	if failed := r.Failed(); failed == 0 {
+		fmt.Fprintf(&b, "  PASS (%d rules)\n", len(r.Rules))
+	} else {
+		fmt.Fprintf(&b, "  FAIL (%d of %d rules)\n", failed, len(r.Rules))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type17"
+)
+
+// memoryDevice builds a Type 17 structure of the given size in MB, type and
+// speed in MT/s
+func memoryDevice(t *testing.T, handle uint16, size uint16, memType type17.MemoryType, speed uint16) gosmbios.Structure {
+	t.Helper()
+	f := make([]byte, 0x22)
+	binary.LittleEndian.PutUint16(f[0x08:], 72)
+	binary.LittleEndian.PutUint16(f[0x0A:], 64)
+	binary.LittleEndian.PutUint16(f[0x0C:], size)
+	f[0x0E] = uint8(type17.FormFactorDIMM)
+	f[0x10] = 1
+	f[0x12] = uint8(memType)
+	binary.LittleEndian.PutUint16(f[0x15:], speed)
+	s, err := gosmbios.NewStructure(type17.StructureType, handle, f[4:], []string{"DIMM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// testTable returns a table with a BIOS, two DDR5 modules and two
+// structures of OEM type 200, which has no decoder
+func testTable(t *testing.T) *gosmbios.SMBIOS {
+	t.Helper()
+	bios, err := (&type0.BIOSInfo{Vendor: "Vendor", Version: "1.0", ReleaseDate: "03/15/2023"}).Build(0x0000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	structures := []gosmbios.Structure{
+		bios,
+		memoryDevice(t, 0x1100, 8192, type17.MemTypeDDR5, 4800),
+		memoryDevice(t, 0x1101, 8192, type17.MemTypeDDR5, 4800),
+	}
+	for i := uint16(0); i < 2; i++ {
+		oem, err := gosmbios.NewStructure(200, 0xC800+i, []byte{0x01, 0x02}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		structures = append(structures, oem)
+	}
+	return &gosmbios.SMBIOS{Structures: structures}
+}
+
+func TestEval(t *testing.T) {
+	sm := testTable(t)
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr string
+	}{
+		// Operator precedence: not binds tighter than and, and than or
+		{"and before or", "true or false and false", true, ""},
+		{"and before or, left", "false and false or true", true, ""},
+		{"parentheses", "(true or false) and false", false, ""},
+		{"not before and", "not false and false", false, ""},
+		{"not of group", "not (false and true)", true, ""},
+		{"symbolic operators", "!false && (false || true)", true, ""},
+		{"comparison before and", "1 < 2 and 3 > 2", true, ""},
+
+		// Numbers and sizes
+		{"sum with size suffix", "sum(type17.Size) >= 16GB", true, ""},
+		{"sum above total", "sum(type17.Size) > 16GB", false, ""},
+		{"hex number", "type17.Speed == 0x12C0", true, ""},
+		{"all values compared", "type17.Speed >= 4800", true, ""},
+
+		// Enums compare by name or by number
+		{"enum by name", "type17.MemoryType == DDR5", true, ""},
+		{"enum by quoted name", `type17.MemoryType == "ddr5"`, true, ""},
+		{"enum by number", "type17.MemoryType == 34", true, ""},
+		{"enum by hex number", "type17.MemoryType == 0x22", true, ""},
+		{"enum by other name", "type17.MemoryType == DDR4", false, ""},
+		{"enum by other number", "type17.MemoryType == 26", false, ""},
+
+		// Dates compare as dates in any accepted layout
+		{"date after", `type0.ReleaseDate > "2022-12-31"`, true, ""},
+		{"date before", `type0.ReleaseDate < "2023-03-16"`, true, ""},
+		{"date equal across layouts", `type0.ReleaseDate == "2023-03-15"`, true, ""},
+		{"date not after", `type0.ReleaseDate > "04/01/2023"`, false, ""},
+
+		// Missing types have no values, so comparisons fail without error
+		{"present missing", "present(type43)", false, ""},
+		{"count missing", "count(type43) == 0", true, ""},
+		{"field of missing", "type43.IsTPM2_0", false, ""},
+
+		// OEM types without a decoder can be counted but not read
+		{"present oem", "present(type200)", true, ""},
+		{"count oem", "count(type200) == 2", true, ""},
+		{"bare oem", "type200", true, ""},
+		{"field of oem", "type200.Value == 1", false, "no decoder"},
+
+		// Unknown fields are errors rather than failures
+		{"unknown field", "type17.NoSuchField == 1", false, `no field or method "NoSuchField"`},
+		{"unknown nested field", "type0.Vendor.NoSuchField", false, `no field or method "NoSuchField"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.expr, err)
+			}
+			got, evidence, err := e.Eval(sm)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval(%q) error = %v, want one containing %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v (evidence %q)", tt.expr, got, tt.want, evidence)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"type17.Size >=",
+		"(true",
+		`"unterminated`,
+		"a = b",
+		"nosuchfunc(type17)",
+		"count(type17, type4)",
+	}
+	for _, expr := range tests {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package qemu exports SMBIOS data in the forms accepted by QEMU's -smbios
+// option, so a physical host's identity can be cloned into a test VM.
+//
+// Two forms are supported:
+//   - Binary blobs for "-smbios file=<blob>": raw concatenated structures,
+//     without an entry point or End-of-Table structure
+//   - Field lists for "-smbios type=N,field=value,...", for the types and
+//     fields QEMU understands (0, 1, 2, 3, 4, 11 and 17)
+package qemu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type1"
+	"github.com/earentir/gosmbios/types/type11"
+	"github.com/earentir/gosmbios/types/type17"
+	"github.com/earentir/gosmbios/types/type2"
+	"github.com/earentir/gosmbios/types/type3"
+	"github.com/earentir/gosmbios/types/type4"
+)
+
+// BlobOptions controls which structures are included in a binary blob
+type BlobOptions struct {
+	// ExcludeTypes lists structure types to leave out. QEMU always generates
+	// its own End-of-Table, so Type 127 is excluded even if not listed.
+	ExcludeTypes []uint8
+	// IncludeInactive keeps Type 126 (Inactive) structures
+	IncludeInactive bool
+}
+
+// included reports whether a structure belongs in the blob
+func (o BlobOptions) included(s *gosmbios.Structure) bool {
+	if s.Header.Type == gosmbios.EndOfTableType {
+		return false
+	}
+	if s.IsInactive() && !o.IncludeInactive {
+		return false
+	}
+	for _, t := range o.ExcludeTypes {
+		if s.Header.Type == t {
+			return false
+		}
+	}
+	return true
+}
+
+// Blob returns all structures concatenated, for use with -smbios file=
+func Blob(sm *gosmbios.SMBIOS, opts BlobOptions) []byte {
+	var blob []byte
+	for i := range sm.Structures {
+		if opts.included(&sm.Structures[i]) {
+			blob = append(blob, sm.Structures[i].Bytes()...)
+		}
+	}
+	return blob
+}
+
+// BlobsByType returns one blob per structure type. QEMU decides which tables
+// it still generates itself from the type of the first structure in each
+// file, so passing one file per type avoids duplicated tables.
+func BlobsByType(sm *gosmbios.SMBIOS, opts BlobOptions) map[uint8][]byte {
+	blobs := make(map[uint8][]byte)
+	for i := range sm.Structures {
+		s := &sm.Structures[i]
+		if opts.included(s) {
+			blobs[s.Header.Type] = append(blobs[s.Header.Type], s.Bytes()...)
+		}
+	}
+	return blobs
+}
+
+// SortedTypes returns the keys of a BlobsByType result in ascending order
+func SortedTypes(blobs map[uint8][]byte) []uint8 {
+	result := make([]uint8, 0, len(blobs))
+	for t := range blobs {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// escape doubles commas, which QEMU's option parser uses as separators
+func escape(s string) string {
+	return strings.ReplaceAll(s, ",", ",,")
+}
+
+// option builds a "type=N,key=value,..." string, skipping empty values.
+// Returns an empty string if no field is set.
+func option(structType uint8, kv ...string) string {
+	var parts []string
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			parts = append(parts, kv[i]+"="+escape(kv[i+1]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("type=%d,%s", structType, strings.Join(parts, ","))
+}
+
+// Options returns the -smbios field lists for the table, one per QEMU type
+// entry. QEMU applies the Type 4 and Type 17 fields to every processor and
+// memory device it creates, so they are taken from the first populated one.
+func Options(sm *gosmbios.SMBIOS) []string {
+	var opts []string
+	add := func(o string) {
+		if o != "" {
+			opts = append(opts, o)
+		}
+	}
+
+	if bios, err := type0.Get(sm); err == nil {
+		release := ""
+		if bios.SystemBIOSMajorRelease != 0xFF {
+			release = fmt.Sprintf("%d.%d", bios.SystemBIOSMajorRelease, bios.SystemBIOSMinorRelease)
+		}
+		uefi := ""
+		if bios.IsUEFI() {
+			uefi = "on"
+		}
+		add(option(type0.StructureType,
+			"vendor", bios.Vendor,
+			"version", bios.Version,
+			"date", bios.ReleaseDate,
+			"release", release,
+			"uefi", uefi))
+	}
+
+	if sys, err := type1.Get(sm); err == nil {
+		uuid := ""
+		if !sys.UUID.IsZero() {
+			uuid = sys.UUID.String()
+		}
+		add(option(type1.StructureType,
+			"manufacturer", sys.Manufacturer,
+			"product", sys.ProductName,
+			"version", sys.Version,
+			"serial", sys.SerialNumber,
+			"uuid", uuid,
+			"sku", sys.SKUNumber,
+			"family", sys.Family))
+	}
+
+	if board, err := type2.Get(sm); err == nil {
+		add(option(type2.StructureType,
+			"manufacturer", board.Manufacturer,
+			"product", board.Product,
+			"version", board.Version,
+			"serial", board.SerialNumber,
+			"asset", board.AssetTag,
+			"location", board.LocationInChassis))
+	}
+
+	if chassis, err := type3.Get(sm); err == nil {
+		add(option(type3.StructureType,
+			"manufacturer", chassis.Manufacturer,
+			"version", chassis.Version,
+			"serial", chassis.SerialNumber,
+			"asset", chassis.AssetTag,
+			"sku", chassis.SKUNumber))
+	}
+
+	if procs, err := type4.GetAll(sm); err == nil {
+		for _, p := range procs {
+			if !p.Status.IsPopulated() {
+				continue
+			}
+			add(option(type4.StructureType,
+				"manufacturer", p.ProcessorManufacturer,
+				"version", p.ProcessorVersion,
+				"serial", p.SerialNumber,
+				"asset", p.AssetTag,
+				"part", p.PartNumber,
+				"max-speed", speed(p.MaxSpeed),
+				"current-speed", speed(p.CurrentSpeed),
+				"processor-id", fmt.Sprintf("%d", p.ProcessorID)))
+			break
+		}
+	}
+
+	if oems, err := type11.GetAll(sm); err == nil {
+		for _, oem := range oems {
+			for _, s := range oem.Strings {
+				add(option(type11.StructureType, "value", s))
+			}
+		}
+	}
+
+	if mems, err := type17.GetPopulated(sm); err == nil && len(mems) > 0 {
+		m := mems[0]
+		add(option(type17.StructureType,
+			"bank", m.BankLocator,
+			"manufacturer", m.Manufacturer,
+			"serial", m.SerialNumber,
+			"asset", m.AssetTag,
+			"part", m.PartNumber,
+			"speed", speed32(m.GetSpeed())))
+	}
+
+	return opts
+}
+
+// speed formats a MHz value, leaving unknown (0) speeds empty
+func speed(mhz uint16) string {
+	return speed32(uint32(mhz))
+}
+
+// speed32 formats a MHz or MT/s value, leaving unknown (0) speeds empty
+func speed32(v uint32) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// Args returns the QEMU command-line arguments ("-smbios", value, ...)
+// for the table, ready to pass to exec.Command
+func Args(sm *gosmbios.SMBIOS) []string {
+	var args []string
+	for _, o := range Options(sm) {
+		args = append(args, "-smbios", o)
+	}
+	return args
+}
+
+// CommandLine returns the arguments as a shell-quoted string, one -smbios
+// option per line
+func CommandLine(sm *gosmbios.SMBIOS) string {
+	var lines []string
+	for _, o := range Options(sm) {
+		lines = append(lines, "-smbios "+shellQuote(o))
+	}
+	return strings.Join(lines, " \\\n")
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
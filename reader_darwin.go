@@ -58,6 +58,7 @@ func readSMBIOS() (*SMBIOS, error) {
 		MajorVersion: 3,
 		MinorVersion: 0,
 		Revision:     0,
+		Synthesized:  true,
 	}
 
 	return &SMBIOS{
@@ -659,9 +660,9 @@ func createProcessorStructures(hwInfo map[string]string) []Structure {
 	binary.Write(&data, binary.LittleEndian, characteristics)
 
 	// Processor Family 2 (offset 0x28)
-	family2 := uint16(0x0102) // ARMv8
+	family2 := uint16(0x0101) // ARMv8
 	if _, ok := cpuInfo["Chip"]; ok {
-		family2 = 0x0102 // ARMv8 for Apple Silicon
+		family2 = 0x0101 // ARMv8 for Apple Silicon
 	}
 	binary.Write(&data, binary.LittleEndian, family2)
 
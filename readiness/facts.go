@@ -0,0 +1,111 @@
+package readiness
+
+import (
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type0"
+	"github.com/earentir/gosmbios/types/type17"
+	"github.com/earentir/gosmbios/types/type4"
+	"github.com/earentir/gosmbios/types/type43"
+)
+
+// CPU is a populated processor socket
+type CPU struct {
+	Socket       string `json:"socket,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// Vendor is the x86 vendor when it can be told from the strings
+	Vendor string `json:"vendor,omitempty"`
+	// Family, Model and Stepping are the display values of the x86 CPUID
+	// signature in ProcessorID, zero when it is not reported
+	Family            uint   `json:"family"`
+	Model             uint   `json:"model"`
+	Stepping          uint   `json:"stepping"`
+	Microarchitecture string `json:"microarchitecture,omitempty"`
+	// Is64Bit is only meaningful if the processor characteristics are
+	// reported (SMBIOS 2.5+)
+	Is64Bit         bool `json:"is_64bit"`
+	Characteristics bool `json:"characteristics_reported"`
+	Cores           int  `json:"cores,omitempty"`
+	MaxSpeedMHz     int  `json:"max_speed_mhz,omitempty"`
+}
+
+// HasSignature returns true if the processor reported a CPUID signature
+func (c *CPU) HasSignature() bool {
+	return c.Family != 0
+}
+
+// Facts are the readiness-relevant values of a table
+type Facts struct {
+	BIOS           bool   `json:"bios"`
+	UEFI           bool   `json:"uefi"`
+	VirtualMachine bool   `json:"virtual_machine"`
+	TPM            bool   `json:"tpm"`
+	TPMSupported   bool   `json:"tpm_supported"`
+	TPMVersion     int    `json:"tpm_version,omitempty"`
+	TPMFamily      string `json:"tpm_family,omitempty"`
+	TPMSpec        string `json:"tpm_spec,omitempty"`
+	CPUs           []CPU  `json:"cpus"`
+	MemoryMB       uint64 `json:"memory_mb"`
+	MemoryDevices  int    `json:"memory_devices"`
+}
+
+// Collect extracts the facts from a table
+func Collect(sm *gosmbios.SMBIOS) *Facts {
+	f := &Facts{CPUs: []CPU{}}
+
+	if bios, err := type0.Get(sm); err == nil {
+		f.BIOS = true
+		f.UEFI = bios.IsUEFI()
+		f.VirtualMachine = bios.IsVirtualMachine()
+	}
+
+	if tpm, err := type43.Get(sm); err == nil {
+		f.TPM = true
+		f.TPMSupported = tpm.IsSupported()
+		f.TPMFamily = tpm.Family()
+		f.TPMSpec = tpm.SpecVersionString()
+		f.TPMVersion = int(tpm.MajorSpecVersion)
+		if f.TPMVersion == 0 {
+			switch {
+			case tpm.Characteristics.IsTPM2_0():
+				f.TPMVersion = 2
+			case tpm.Characteristics.IsTPM1_2():
+				f.TPMVersion = 1
+			}
+		}
+	}
+
+	if procs, err := type4.GetAll(sm); err == nil {
+		for _, p := range procs {
+			if !p.Status.IsPopulated() {
+				continue
+			}
+			c := CPU{
+				Socket:       strings.TrimSpace(p.SocketDesignation),
+				Version:      strings.TrimSpace(p.ProcessorVersion),
+				Manufacturer: strings.TrimSpace(p.ProcessorManufacturer),
+				Is64Bit:      p.ProcessorCharacteristics.Is64Bit(),
+				Cores:        int(p.GetCoreCount()),
+				MaxSpeedMHz:  int(p.MaxSpeed),
+			}
+			c.Characteristics = p.ProcessorCharacteristics != 0 && !p.ProcessorCharacteristics.Has(type4.CharUnknown)
+			c.Vendor = p.X86Vendor()
+			if id, ok := p.CPUID(); ok {
+				c.Family, c.Model, c.Stepping = uint(id.DisplayFamily), uint(id.DisplayModel), uint(id.Stepping)
+				c.Microarchitecture = type4.LookupMicroarchitecture(c.Vendor, id)
+			}
+			f.CPUs = append(f.CPUs, c)
+		}
+	}
+
+	if mems, err := type17.GetPopulated(sm); err == nil {
+		for _, m := range mems {
+			f.MemoryMB += m.Size
+			f.MemoryDevices++
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,201 @@
+package readiness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/earentir/gosmbios/policy"
+)
+
+// CPUMatch selects processors by vendor and CPUID signature
+type CPUMatch struct {
+	Name   string `json:"name,omitempty"`
+	Vendor string `json:"vendor"`
+	Family uint   `json:"family"`
+	// Models lists the accepted display models; if empty, ModelMin and
+	// ModelMax bound them (a ModelMax of 0 means no upper bound)
+	Models      []uint `json:"models,omitempty"`
+	ModelMin    uint   `json:"model_min,omitempty"`
+	ModelMax    uint   `json:"model_max,omitempty"`
+	MinStepping uint   `json:"min_stepping,omitempty"`
+}
+
+// Matches returns true if the processor matches
+func (m *CPUMatch) Matches(c *CPU) bool {
+	if !strings.EqualFold(m.Vendor, c.Vendor) || m.Family != c.Family || c.Stepping < m.MinStepping {
+		return false
+	}
+	if len(m.Models) > 0 {
+		for _, model := range m.Models {
+			if model == c.Model {
+				return true
+			}
+		}
+		return false
+	}
+	return c.Model >= m.ModelMin && (m.ModelMax == 0 || c.Model <= m.ModelMax)
+}
+
+// Profile is the set of hardware requirements of an operating system.
+// Zero values disable a requirement.
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Require64Bit bool `json:"require_64bit,omitempty"`
+	MinCores     int  `json:"min_cores,omitempty"`
+	MinSpeedMHz  int  `json:"min_speed_mhz,omitempty"`
+	// CPUs lists the supported processors (empty for any), described in
+	// reports by CPULabel
+	CPUs     []CPUMatch `json:"cpus,omitempty"`
+	CPULabel string     `json:"cpu_label,omitempty"`
+
+	MinMemoryMB       uint64 `json:"min_memory_mb,omitempty"`
+	MinTPMVersion     int    `json:"min_tpm_version,omitempty"`
+	RequireUEFI       bool   `json:"require_uefi,omitempty"`
+	RequireSecureBoot bool   `json:"require_secure_boot,omitempty"`
+
+	// Rules are additional policy rules, each reported as a requirement
+	Rules []policy.Rule `json:"rules,omitempty"`
+}
+
+// Windows 11 supported processors, after Microsoft's published lists
+// (Intel 8th generation and later, AMD Zen+ and later)
+var windows11CPUs = []CPUMatch{
+	{Name: "Coffee Lake / Whiskey Lake / Amber Lake / Comet Lake", Vendor: "Intel", Family: 6, Models: []uint{0x8E, 0x9E}, MinStepping: 0xA},
+	{Name: "Cascade Lake / Cooper Lake", Vendor: "Intel", Family: 6, Models: []uint{0x55}, MinStepping: 5},
+	{Name: "Gemini Lake Refresh", Vendor: "Intel", Family: 6, Models: []uint{0x7A}, MinStepping: 8},
+	{Name: "Cannon Lake", Vendor: "Intel", Family: 6, Models: []uint{0x66}},
+	{Name: "Comet Lake", Vendor: "Intel", Family: 6, Models: []uint{0xA5, 0xA6}},
+	{Name: "Ice Lake", Vendor: "Intel", Family: 6, Models: []uint{0x6A, 0x6C, 0x7D, 0x7E}},
+	{Name: "Elkhart Lake / Jasper Lake", Vendor: "Intel", Family: 6, Models: []uint{0x96, 0x9C}},
+	{Name: "Tiger Lake / Rocket Lake", Vendor: "Intel", Family: 6, Models: []uint{0x8C, 0x8D, 0xA7}},
+	{Name: "Alder Lake / Raptor Lake", Vendor: "Intel", Family: 6, Models: []uint{0x97, 0x9A, 0xBE, 0xB7, 0xBA, 0xBF}},
+	{Name: "Meteor Lake / Lunar Lake / Arrow Lake", Vendor: "Intel", Family: 6, Models: []uint{0xAA, 0xAC, 0xBD, 0xC5, 0xC6}},
+	{Name: "Sapphire Rapids / Emerald Rapids / Granite Rapids / Sierra Forest", Vendor: "Intel", Family: 6, Models: []uint{0x8F, 0xCF, 0xAD, 0xAE, 0xAF}},
+	{Name: "Zen+ / Zen 2", Vendor: "AMD", Family: 0x17, ModelMin: 0x08, ModelMax: 0x10},
+	{Name: "Zen+ / Zen 2", Vendor: "AMD", Family: 0x17, ModelMin: 0x12},
+	{Name: "Zen 3 / Zen 4", Vendor: "AMD", Family: 0x19},
+	{Name: "Zen 5", Vendor: "AMD", Family: 0x1A},
+}
+
+// x86-64-v3 (AVX2, BMI1/2, FMA, MOVBE) capable processors. Some Pentium and
+// Celeron parts of these generations have AVX disabled.
+var x8664v3CPUs = []CPUMatch{
+	{Name: "Haswell / Broadwell", Vendor: "Intel", Family: 6, Models: []uint{0x3C, 0x3F, 0x45, 0x46, 0x3D, 0x47, 0x4F, 0x56}},
+	{Name: "Skylake / Kaby Lake / Coffee Lake / Comet Lake", Vendor: "Intel", Family: 6, Models: []uint{0x4E, 0x5E, 0x55, 0x8E, 0x9E, 0xA5, 0xA6}},
+	{Name: "Cannon Lake / Ice Lake / Tiger Lake / Rocket Lake", Vendor: "Intel", Family: 6, Models: []uint{0x66, 0x6A, 0x6C, 0x7D, 0x7E, 0x8C, 0x8D, 0xA7}},
+	{Name: "Alder Lake / Raptor Lake", Vendor: "Intel", Family: 6, Models: []uint{0x97, 0x9A, 0xBE, 0xB7, 0xBA, 0xBF}},
+	{Name: "Meteor Lake / Lunar Lake / Arrow Lake", Vendor: "Intel", Family: 6, Models: []uint{0xAA, 0xAC, 0xBD, 0xC5, 0xC6}},
+	{Name: "Sapphire Rapids / Emerald Rapids / Granite Rapids / Sierra Forest", Vendor: "Intel", Family: 6, Models: []uint{0x8F, 0xCF, 0xAD, 0xAE, 0xAF}},
+	{Name: "Excavator", Vendor: "AMD", Family: 0x15, ModelMin: 0x60, ModelMax: 0x7F},
+	{Name: "Zen / Zen+ / Zen 2", Vendor: "AMD", Family: 0x17},
+	{Name: "Dhyana", Vendor: "Hygon", Family: 0x18},
+	{Name: "Zen 3 / Zen 4", Vendor: "AMD", Family: 0x19},
+	{Name: "Zen 5", Vendor: "AMD", Family: 0x1A},
+}
+
+// Built-in profiles
+var (
+	Windows11 = &Profile{
+		Name:              "windows11",
+		Description:       "Windows 11",
+		Require64Bit:      true,
+		MinCores:          2,
+		MinSpeedMHz:       1000,
+		CPUs:              windows11CPUs,
+		CPULabel:          "Windows 11 supported processor",
+		MinMemoryMB:       4 * 1024,
+		MinTPMVersion:     2,
+		RequireUEFI:       true,
+		RequireSecureBoot: true,
+	}
+	RHEL10 = &Profile{
+		Name:         "rhel10",
+		Description:  "Red Hat Enterprise Linux 10 (x86-64-v3)",
+		Require64Bit: true,
+		CPUs:         x8664v3CPUs,
+		CPULabel:     "x86-64-v3 capable processor",
+		MinMemoryMB:  2 * 1024,
+	}
+)
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]*Profile{
+		Windows11.Name: Windows11,
+		RHEL10.Name:    RHEL10,
+	}
+)
+
+// RegisterProfile adds a profile, replacing any profile of the same name
+func RegisterProfile(p *Profile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles[strings.ToLower(p.Name)] = p
+}
+
+// LookupProfile returns a registered profile by name
+func LookupProfile(name string) (*Profile, bool) {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	p, ok := profiles[strings.ToLower(name)]
+	return p, ok
+}
+
+// Profiles returns the registered profiles sorted by name
+func Profiles() []*Profile {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	list := make([]*Profile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ErrInvalidProfile is returned for profiles without a name or with
+// invalid rules
+var ErrInvalidProfile = errors.New("smbios: invalid readiness profile")
+
+// ParseProfiles reads one JSON profile or an array of them
+func ParseProfiles(data []byte) ([]*Profile, error) {
+	var list []*Profile
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+	} else {
+		var p Profile
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, err
+		}
+		list = []*Profile{&p}
+	}
+
+	for i, p := range list {
+		if p == nil || p.Name == "" {
+			return nil, fmt.Errorf("%w: profile %d has no name", ErrInvalidProfile, i)
+		}
+		rules := policy.Policy{Rules: p.Rules}
+		if err := rules.Compile(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidProfile, p.Name, err)
+		}
+	}
+	return list, nil
+}
+
+// LoadProfiles reads profiles from a JSON file
+func LoadProfiles(filename string) ([]*Profile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfiles(data)
+}
@@ -0,0 +1,292 @@
+// Package readiness reports whether hardware meets the requirements of an
+// operating system, such as Windows 11 or a RHEL 10 x86-64-v3 baseline,
+// from its SMBIOS table alone, so it works offline against archived dumps.
+//
+// Requirements are checked against the TPM (Type 43), UEFI support (Type 0
+// BIOS Characteristics Extension Byte 2), the processors (Type 4 family,
+// CPUID signature, cores and speed) and installed memory (Type 17). Each
+// requirement passes, fails, or is unknown when the table does not record
+// it; SMBIOS has no Secure Boot state, so Secure Boot is judged by whether
+// the firmware is UEFI.
+package readiness
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+)
+
+// Status is the outcome of a requirement or a whole profile
+type Status string
+
+// Statuses
+const (
+	Pass    Status = "pass"
+	Fail    Status = "fail"
+	Unknown Status = "unknown" // Not recorded in the table
+)
+
+// Check is the outcome of one requirement
+type Check struct {
+	Requirement string   `json:"requirement"`
+	Status      Status   `json:"status"`
+	Detail      string   `json:"detail,omitempty"`
+	Evidence    []string `json:"evidence,omitempty"`
+}
+
+// Report is the readiness of one table for one profile
+type Report struct {
+	Source      string  `json:"source,omitempty"`
+	Profile     string  `json:"profile"`
+	Description string  `json:"description,omitempty"`
+	Status      Status  `json:"status"`
+	Checks      []Check `json:"checks"`
+	Facts       *Facts  `json:"facts"`
+}
+
+// Evaluate checks a table against a profile
+func Evaluate(sm *gosmbios.SMBIOS, p *Profile) *Report {
+	f := Collect(sm)
+	r := &Report{Profile: p.Name, Description: p.Description, Checks: []Check{}, Facts: f}
+
+	if p.Require64Bit {
+		r.add(check64Bit(f))
+	}
+	if p.MinCores > 0 {
+		r.add(checkCores(f, p.MinCores))
+	}
+	if p.MinSpeedMHz > 0 {
+		r.add(checkSpeed(f, p.MinSpeedMHz))
+	}
+	if len(p.CPUs) > 0 {
+		r.add(checkCPUs(f, p))
+	}
+	if p.MinMemoryMB > 0 {
+		r.add(checkMemory(f, p.MinMemoryMB))
+	}
+	if p.MinTPMVersion > 0 {
+		r.add(checkTPM(f, p.MinTPMVersion))
+	}
+	if p.RequireUEFI {
+		r.add(checkUEFI(f))
+	}
+	if p.RequireSecureBoot {
+		r.add(checkSecureBoot(f))
+	}
+	for i := range p.Rules {
+		res := p.Rules[i].Evaluate(sm)
+		c := Check{Requirement: res.Name, Status: Pass, Evidence: res.Evidence}
+		if res.Name != res.Expr {
+			c.Detail = res.Expr
+		}
+		if !res.Passed {
+			c.Status = Fail
+		}
+		if res.Error != "" {
+			c.Detail = res.Error
+		}
+		r.add(c)
+	}
+
+	r.Status = Pass
+	for _, c := range r.Checks {
+		switch {
+		case c.Status == Fail:
+			r.Status = Fail
+		case c.Status == Unknown && r.Status == Pass:
+			r.Status = Unknown
+		}
+	}
+	return r
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
+
+// noCPU is the check result when no populated processor is reported
+func noCPU(requirement string) Check {
+	return Check{Requirement: requirement, Status: Unknown, Detail: "no populated processor (Type 4) reported"}
+}
+
+func check64Bit(f *Facts) Check {
+	const req = "64-bit processor"
+	if len(f.CPUs) == 0 {
+		return noCPU(req)
+	}
+	for _, c := range f.CPUs {
+		if !c.Characteristics {
+			return Check{Requirement: req, Status: Unknown, Detail: c.Socket + ": processor characteristics not reported"}
+		}
+		if !c.Is64Bit {
+			return Check{Requirement: req, Status: Fail, Detail: c.Socket + ": not 64-bit capable"}
+		}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: "64-bit capable"}
+}
+
+func checkCores(f *Facts, min int) Check {
+	req := fmt.Sprintf("At least %d cores", min)
+	if len(f.CPUs) == 0 {
+		return noCPU(req)
+	}
+	total := 0
+	for _, c := range f.CPUs {
+		if c.Cores == 0 {
+			return Check{Requirement: req, Status: Unknown, Detail: c.Socket + ": core count not reported"}
+		}
+		total += c.Cores
+	}
+	if total < min {
+		return Check{Requirement: req, Status: Fail, Detail: fmt.Sprintf("%d cores", total)}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: fmt.Sprintf("%d cores", total)}
+}
+
+func checkSpeed(f *Facts, min int) Check {
+	req := fmt.Sprintf("At least %d MHz", min)
+	if len(f.CPUs) == 0 {
+		return noCPU(req)
+	}
+	for _, c := range f.CPUs {
+		if c.MaxSpeedMHz == 0 {
+			return Check{Requirement: req, Status: Unknown, Detail: c.Socket + ": speed not reported"}
+		}
+		if c.MaxSpeedMHz < min {
+			return Check{Requirement: req, Status: Fail, Detail: fmt.Sprintf("%s: %d MHz", c.Socket, c.MaxSpeedMHz)}
+		}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: fmt.Sprintf("%d MHz", f.CPUs[0].MaxSpeedMHz)}
+}
+
+func checkCPUs(f *Facts, p *Profile) Check {
+	req := p.CPULabel
+	if req == "" {
+		req = "Supported processor"
+	}
+	if len(f.CPUs) == 0 {
+		return noCPU(req)
+	}
+
+	var names []string
+	for _, c := range f.CPUs {
+		if !c.HasSignature() {
+			return Check{Requirement: req, Status: Unknown, Detail: c.Socket + ": no x86 CPUID signature reported"}
+		}
+		sig := fmt.Sprintf("%s family 0x%X model 0x%X stepping %d", c.Vendor, c.Family, c.Model, c.Stepping)
+		var match *CPUMatch
+		for i := range p.CPUs {
+			if p.CPUs[i].Matches(&c) {
+				match = &p.CPUs[i]
+				break
+			}
+		}
+		if match == nil {
+			return Check{Requirement: req, Status: Fail, Detail: sig + " (" + c.Version + ") not supported"}
+		}
+		name := sig
+		if match.Name != "" {
+			name += " (" + match.Name + ")"
+		}
+		names = append(names, name)
+	}
+	return Check{Requirement: req, Status: Pass, Detail: strings.Join(names, "; ")}
+}
+
+func checkMemory(f *Facts, minMB uint64) Check {
+	req := fmt.Sprintf("At least %s memory", formatMB(minMB))
+	if f.MemoryDevices == 0 {
+		return Check{Requirement: req, Status: Unknown, Detail: "no populated memory devices (Type 17) reported"}
+	}
+	detail := fmt.Sprintf("%s in %d device(s)", formatMB(f.MemoryMB), f.MemoryDevices)
+	if f.MemoryMB < minMB {
+		return Check{Requirement: req, Status: Fail, Detail: detail}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: detail}
+}
+
+func checkTPM(f *Facts, min int) Check {
+	req := fmt.Sprintf("TPM %d.0 or later", min)
+	if min == 1 {
+		req = "TPM 1.2 or later"
+	}
+	switch {
+	case !f.TPM:
+		return Check{Requirement: req, Status: Unknown, Detail: "no TPM Device (Type 43) reported; the TPM may be absent, disabled or not described"}
+	case !f.TPMSupported:
+		return Check{Requirement: req, Status: Fail, Detail: "TPM device not supported"}
+	case f.TPMVersion < min:
+		return Check{Requirement: req, Status: Fail, Detail: fmt.Sprintf("%s (spec %s)", f.TPMFamily, f.TPMSpec)}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: fmt.Sprintf("%s (spec %s)", f.TPMFamily, f.TPMSpec)}
+}
+
+func checkUEFI(f *Facts) Check {
+	const req = "UEFI firmware"
+	switch {
+	case !f.BIOS:
+		return Check{Requirement: req, Status: Unknown, Detail: "no BIOS Information (Type 0) reported"}
+	case !f.UEFI:
+		return Check{Requirement: req, Status: Fail, Detail: "UEFI not supported (legacy BIOS)"}
+	}
+	return Check{Requirement: req, Status: Pass, Detail: "UEFI supported"}
+}
+
+func checkSecureBoot(f *Facts) Check {
+	const req = "Secure Boot capable"
+	switch {
+	case !f.BIOS:
+		return Check{Requirement: req, Status: Unknown, Detail: "no BIOS Information (Type 0) reported"}
+	case !f.UEFI:
+		return Check{Requirement: req, Status: Fail, Detail: "Secure Boot requires UEFI firmware"}
+	}
+	detail := "UEFI firmware; whether Secure Boot is enabled is not recorded in SMBIOS"
+	if f.VirtualMachine {
+		detail += " (virtual machine: depends on the hypervisor firmware)"
+	}
+	return Check{Requirement: req, Status: Pass, Detail: detail}
+}
+
+// formatMB formats a size in megabytes
+func formatMB(mb uint64) string {
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%d GB", mb/1024)
+	}
+	return fmt.Sprintf("%d MB", mb)
+}
+
+// statusText is the text report label of each status
+var statusText = map[Status]string{
+	Pass:    "READY",
+	Fail:    "NOT READY",
+	Unknown: "UNDETERMINED",
+}
+
+// WriteText writes one line per requirement followed by the overall status
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	name := r.Profile
+	if r.Description != "" {
+		name = r.Description
+	}
+	if r.Source != "" {
+		fmt.Fprintf(&b, "%s: %s\n", r.Source, name)
+	} else {
+		fmt.Fprintf(&b, "%s\n", name)
+	}
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "  [%-7s] %s", strings.ToUpper(string(c.Status)), c.Requirement)
+		if c.Detail != "" {
+			fmt.Fprintf(&b, ": %s", c.Detail)
+		}
+		b.WriteString("\n")
+		for _, e := range c.Evidence {
+			fmt.Fprintf(&b, "            - %s\n", e)
+		}
+	}
+	fmt.Fprintf(&b, "  %s\n", statusText[r.Status])
+	_, err := io.WriteString(w, b.String())
+	return err
+}
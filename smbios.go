@@ -41,6 +41,9 @@ type EntryPoint struct {
 	StructureCount   uint16 // Only for 2.x (not reliable for 3.x)
 	BCDRevision      uint8  // Only for 2.x
 	EntryPointLength uint8
+	// Synthesized is set when the source has no entry point, e.g. a raw
+	// structure table, and the version is assumed rather than read
+	Synthesized bool
 }
 
 // String returns a human-readable version string
@@ -60,9 +63,10 @@ type Header struct {
 
 // Structure represents a single SMBIOS structure with its data and strings
 type Structure struct {
-	Header  Header
-	Data    []byte   // Raw formatted section data (includes header)
-	Strings []string // String table entries
+	Header     Header
+	Data       []byte   // Raw formatted section data (includes header)
+	Strings    []string // String table entries, decoded with the active StringPolicy
+	RawStrings [][]byte // String table entries as raw bytes (nil for synthesized structures)
 }
 
 // GetString returns a string from the string table (1-indexed as per SMBIOS spec)
@@ -141,13 +145,23 @@ func Read() (*SMBIOS, error) {
 
 // ReadFromFile reads SMBIOS data from a binary dump file
 // The file format is a simple binary format:
-// - 32 bytes: Entry point header
+// - 28 bytes: "SMBIOSRAW" file header
 // - Remaining: Raw SMBIOS table data
+// Files without the header are read as raw structure tables, such as QEMU
+// "-smbios file=" blobs or copies of /sys/firmware/dmi/tables/DMI
 func ReadFromFile(filename string) (*SMBIOS, error) {
 	return readSMBIOSFromFile(filename)
 }
 
+// ReadFromBytes parses the contents of a dump file, in any format accepted
+// by ReadFromFile, for example when reading dumps from an archive
+func ReadFromBytes(data []byte) (*SMBIOS, error) {
+	return readSMBIOSFromBytes(data)
+}
+
 // WriteToFile writes SMBIOS data to a binary dump file
+// String tables are written from RawStrings when present, so the original
+// bytes are preserved regardless of the StringPolicy used to decode them
 func (sm *SMBIOS) WriteToFile(filename string) error {
 	return writeSMBIOSToFile(sm, filename)
 }
@@ -0,0 +1,206 @@
+package gosmbios
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringEncoding selects how raw string-table bytes are converted to Go strings
+type StringEncoding int
+
+const (
+	EncodingRaw    StringEncoding = iota // Bytes are copied as-is (may be invalid UTF-8)
+	EncodingUTF8                         // Assume UTF-8, replace invalid sequences with U+FFFD
+	EncodingLatin1                       // Transcode from ISO 8859-1
+	EncodingCP437                        // Transcode from IBM code page 437
+	EncodingAuto                         // Use UTF-8 if valid, otherwise transcode from Latin-1
+)
+
+// String returns a human-readable encoding name
+func (e StringEncoding) String() string {
+	switch e {
+	case EncodingRaw:
+		return "Raw"
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingLatin1:
+		return "Latin-1"
+	case EncodingCP437:
+		return "CP437"
+	case EncodingAuto:
+		return "Auto"
+	default:
+		return "Unknown"
+	}
+}
+
+// StringPolicy controls how string-table entries are decoded into Structure.Strings.
+// The zero value keeps the raw bytes unchanged.
+type StringPolicy struct {
+	Encoding       StringEncoding
+	TrimSpace      bool // Remove leading and trailing white space
+	ReplaceControl bool // Replace control characters with ControlReplacement
+	// ControlReplacement is substituted for each control character when
+	// ReplaceControl is set. A zero rune removes the character instead.
+	ControlReplacement rune
+}
+
+// defaultStringPolicyMu guards defaultStringPolicy
+var defaultStringPolicyMu sync.RWMutex
+
+// defaultStringPolicy is the policy returned by DefaultStringPolicy
+var defaultStringPolicy StringPolicy
+
+// DefaultStringPolicy returns the policy used by ParseStructures and
+// therefore by Read and ReadFromFile
+func DefaultStringPolicy() StringPolicy {
+	defaultStringPolicyMu.RLock()
+	defer defaultStringPolicyMu.RUnlock()
+	return defaultStringPolicy
+}
+
+// SetDefaultStringPolicy sets the policy used by ParseStructures. Set it
+// before reading to decode every table the same way, or call
+// ApplyStringPolicy on an already-read table. It is safe to call while
+// other goroutines read tables.
+func SetDefaultStringPolicy(p StringPolicy) {
+	defaultStringPolicyMu.Lock()
+	defer defaultStringPolicyMu.Unlock()
+	defaultStringPolicy = p
+}
+
+// CleanStringPolicy returns a policy suited for display and inventory use:
+// auto-detected encoding, trimmed, with control characters removed
+func CleanStringPolicy() StringPolicy {
+	return StringPolicy{
+		Encoding:       EncodingAuto,
+		TrimSpace:      true,
+		ReplaceControl: true,
+	}
+}
+
+// StringValue is a string-table entry together with its raw bytes
+type StringValue struct {
+	Value     string // Decoded string, as stored in Structure.Strings
+	Raw       []byte // Bytes as found in the string table
+	ValidUTF8 bool   // Whether Raw is valid UTF-8
+}
+
+// cp437High maps CP437 bytes 0x80-0xFF to Unicode
+var cp437High = [128]rune{
+	'Ç', 'ü', 'é', 'â', 'ä', 'à', 'å', 'ç', 'ê', 'ë', 'è', 'ï', 'î', 'ì', 'Ä', 'Å',
+	'É', 'æ', 'Æ', 'ô', 'ö', 'ò', 'û', 'ù', 'ÿ', 'Ö', 'Ü', '¢', '£', '¥', '₧', 'ƒ',
+	'á', 'í', 'ó', 'ú', 'ñ', 'Ñ', 'ª', 'º', '¿', '⌐', '¬', '½', '¼', '¡', '«', '»',
+	'░', '▒', '▓', '│', '┤', '╡', '╢', '╖', '╕', '╣', '║', '╗', '╝', '╜', '╛', '┐',
+	'└', '┴', '┬', '├', '─', '┼', '╞', '╟', '╚', '╔', '╩', '╦', '╠', '═', '╬', '╧',
+	'╨', '╤', '╥', '╙', '╘', '╒', '╓', '╫', '╪', '┘', '┌', '█', '▄', '▌', '▐', '▀',
+	'α', 'ß', 'Γ', 'π', 'Σ', 'σ', 'µ', 'τ', 'Φ', 'Θ', 'Ω', 'δ', '∞', 'φ', 'ε', '∩',
+	'≡', '±', '≥', '≤', '⌠', '⌡', '÷', '≈', '°', '∙', '·', '√', 'ⁿ', '²', '■', '\u00A0',
+}
+
+// Decode converts raw string-table bytes to a Go string according to the policy
+func (p StringPolicy) Decode(raw []byte) string {
+	var s string
+	switch p.Encoding {
+	case EncodingUTF8:
+		s = strings.ToValidUTF8(string(raw), "\uFFFD")
+	case EncodingLatin1:
+		s = decodeLatin1(raw)
+	case EncodingCP437:
+		s = decodeCP437(raw)
+	case EncodingAuto:
+		if utf8.Valid(raw) {
+			s = string(raw)
+		} else {
+			s = decodeLatin1(raw)
+		}
+	default:
+		s = string(raw)
+	}
+
+	if p.ReplaceControl {
+		s = strings.Map(func(r rune) rune {
+			if !unicode.IsControl(r) {
+				return r
+			}
+			if p.ControlReplacement == 0 {
+				return -1
+			}
+			return p.ControlReplacement
+		}, s)
+	}
+
+	if p.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+
+	return s
+}
+
+// decodeLatin1 transcodes ISO 8859-1 bytes, which map 1:1 to Unicode code points
+func decodeLatin1(raw []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for _, b := range raw {
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
+
+// decodeCP437 transcodes IBM code page 437 bytes. The low half is treated as ASCII.
+func decodeCP437(raw []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for _, b := range raw {
+		if b < 0x80 {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteRune(cp437High[b-0x80])
+		}
+	}
+	return sb.String()
+}
+
+// GetStringValue returns a string-table entry with its raw bytes and UTF-8 validity
+// (1-indexed as per SMBIOS spec). Returns a zero StringValue if index is 0 or out of bounds.
+func (s *Structure) GetStringValue(index uint8) StringValue {
+	if index == 0 || int(index) > len(s.Strings) {
+		return StringValue{}
+	}
+
+	value := s.Strings[index-1]
+	raw := []byte(value)
+	if int(index) <= len(s.RawStrings) {
+		raw = s.RawStrings[index-1]
+	}
+
+	return StringValue{
+		Value:     value,
+		Raw:       raw,
+		ValidUTF8: utf8.Valid(raw),
+	}
+}
+
+// ApplyStringPolicy re-decodes the string table from RawStrings.
+// Structures without raw strings (e.g. synthesized ones) are left unchanged.
+func (s *Structure) ApplyStringPolicy(p StringPolicy) {
+	if s.RawStrings == nil {
+		return
+	}
+	decoded := make([]string, len(s.RawStrings))
+	for i, raw := range s.RawStrings {
+		decoded[i] = p.Decode(raw)
+	}
+	s.Strings = decoded
+}
+
+// ApplyStringPolicy re-decodes the string tables of all structures.
+// Values already parsed by typeN packages are not affected, so apply the
+// policy before calling their Get or Parse functions.
+func (sm *SMBIOS) ApplyStringPolicy(p StringPolicy) {
+	for i := range sm.Structures {
+		sm.Structures[i].ApplyStringPolicy(p)
+	}
+}
@@ -8,6 +8,7 @@ import (
 func ParseStructures(tableData []byte, maxStructures int) ([]Structure, error) {
 	var structures []Structure
 	offset := 0
+	policy := DefaultStringPolicy()
 
 	for offset < len(tableData) {
 		// Check if we have enough data for the header
@@ -24,6 +25,9 @@ func ParseStructures(tableData []byte, maxStructures int) ([]Structure, error) {
 
 		// End-of-Table structure (Type 127)
 		if header.Type == 127 {
+			if offset+int(header.Length) > len(tableData) {
+				return nil, ErrInvalidStructure
+			}
 			structures = append(structures, Structure{
 				Header:  header,
 				Data:    tableData[offset : offset+int(header.Length)],
@@ -48,13 +52,15 @@ func ParseStructures(tableData []byte, maxStructures int) ([]Structure, error) {
 
 		// Parse string table
 		stringStart := offset + int(header.Length)
-		strings, stringEnd := parseStringTable(tableData, stringStart)
+		rawStrings, stringEnd := parseStringTable(tableData, stringStart)
 
-		structures = append(structures, Structure{
-			Header:  header,
-			Data:    formattedSection,
-			Strings: strings,
-		})
+		s := Structure{
+			Header:     header,
+			Data:       formattedSection,
+			RawStrings: rawStrings,
+		}
+		s.ApplyStringPolicy(policy)
+		structures = append(structures, s)
 
 		offset = stringEnd
 
@@ -68,11 +74,11 @@ func ParseStructures(tableData []byte, maxStructures int) ([]Structure, error) {
 }
 
 // parseStringTable parses the null-terminated string table following a structure
-// Returns the strings and the offset after the string table (after double-null terminator)
+// Returns the raw string bytes and the offset after the string table (after double-null terminator)
 // Per SMBIOS spec: strings are null-terminated, table ends with additional null (double-null)
 // Empty string table is just \0\0 (two consecutive nulls)
-func parseStringTable(data []byte, start int) ([]string, int) {
-	var strings []string
+func parseStringTable(data []byte, start int) ([][]byte, int) {
+	var strings [][]byte
 	current := start
 
 	for current < len(data) {
@@ -97,7 +103,9 @@ func parseStringTable(data []byte, start int) ([]string, int) {
 		}
 
 		if end > current {
-			strings = append(strings, string(data[current:end]))
+			raw := make([]byte, end-current)
+			copy(raw, data[current:end])
+			strings = append(strings, raw)
 		}
 
 		// Move past the null terminator of this string
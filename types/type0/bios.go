@@ -3,6 +3,7 @@
 package type0
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/earentir/gosmbios"
@@ -233,3 +234,39 @@ func (b *BIOSInfo) IsUEFI() bool {
 func (b *BIOSInfo) IsVirtualMachine() bool {
 	return b.CharacteristicsExt2.Has(CharExt2VirtualMachine)
 }
+
+// Sanitized returns a copy with placeholder string fields (such as
+// "To Be Filled By O.E.M.") replaced by empty strings
+func (b *BIOSInfo) Sanitized() *BIOSInfo {
+	c := *b
+	c.Vendor = gosmbios.Sanitize(b.Vendor)
+	c.Version = gosmbios.Sanitize(b.Version)
+	c.ReleaseDate = gosmbios.Sanitize(b.ReleaseDate)
+	return &c
+}
+
+// Build encodes the BIOS information as an SMBIOS 3.1+ structure with the
+// given handle
+func (b *BIOSInfo) Build(handle uint16) (gosmbios.Structure, error) {
+	var st gosmbios.StringTable
+	f := make([]byte, 0x1A)
+	f[0x04] = st.Add(b.Vendor)
+	f[0x05] = st.Add(b.Version)
+	binary.LittleEndian.PutUint16(f[0x06:], b.StartingAddressSegment)
+	f[0x08] = st.Add(b.ReleaseDate)
+	f[0x09] = b.ROMSize
+	binary.LittleEndian.PutUint64(f[0x0A:], uint64(b.Characteristics))
+	f[0x12] = uint8(b.CharacteristicsExt1)
+	f[0x13] = uint8(b.CharacteristicsExt2)
+	f[0x14] = b.SystemBIOSMajorRelease
+	f[0x15] = b.SystemBIOSMinorRelease
+	f[0x16] = b.EmbeddedControllerMajorRelease
+	f[0x17] = b.EmbeddedControllerMinorRelease
+	extSize := b.ExtendedROMSize & 0x3FFF
+	if b.ExtendedROMSizeUnit == ROMSizeUnitGB {
+		extSize |= 0x4000
+	}
+	binary.LittleEndian.PutUint16(f[0x18:], extSize)
+
+	return gosmbios.NewStructure(StructureType, handle, f[4:], st.Strings())
+}
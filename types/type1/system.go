@@ -4,6 +4,7 @@ package type1
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"github.com/earentir/gosmbios"
@@ -25,6 +26,9 @@ type SystemInfo struct {
 	Family       string // SMBIOS 2.4+
 }
 
+// ErrInvalidUUID is returned by ParseUUID for malformed input
+var ErrInvalidUUID = errors.New("smbios: invalid UUID")
+
 // UUID represents a 128-bit Universal Unique Identifier
 type UUID [16]byte
 
@@ -112,6 +116,22 @@ func (u UUID) IsInvalid() bool {
 	return true
 }
 
+// placeholderUUIDs are UUIDs shipped by firmware vendors on many boards
+// instead of a unique value
+var placeholderUUIDs = map[string]bool{
+	"03000200-0400-0500-0006-000700080009": true,
+}
+
+// IsPlaceholder returns true if UUID is a known vendor default shared by many systems
+func (u UUID) IsPlaceholder() bool {
+	return placeholderUUIDs[u.String()]
+}
+
+// IsMeaningful returns true if UUID is set, settable and not a known placeholder
+func (u UUID) IsMeaningful() bool {
+	return !u.IsZero() && !u.IsInvalid() && !u.IsPlaceholder()
+}
+
 // Parse parses a System Information structure from raw SMBIOS data
 func Parse(s *gosmbios.Structure) (*SystemInfo, error) {
 	if s == nil || s.Header.Type != StructureType {
@@ -169,3 +189,57 @@ func (si *SystemInfo) DisplayName() string {
 	}
 	return "Unknown System"
 }
+
+// Sanitized returns a copy with placeholder string fields (such as
+// "System Serial Number" or "Default string") replaced by empty strings,
+// and the UUID zeroed if it is not meaningful
+func (si *SystemInfo) Sanitized() *SystemInfo {
+	c := *si
+	c.Manufacturer = gosmbios.Sanitize(si.Manufacturer)
+	c.ProductName = gosmbios.Sanitize(si.ProductName)
+	c.Version = gosmbios.Sanitize(si.Version)
+	c.SerialNumber = gosmbios.Sanitize(si.SerialNumber)
+	c.SKUNumber = gosmbios.Sanitize(si.SKUNumber)
+	c.Family = gosmbios.Sanitize(si.Family)
+	if !si.UUID.IsMeaningful() {
+		c.UUID = UUID{}
+	}
+	return &c
+}
+
+// ParseUUID parses a UUID in standard 8-4-4-4-12 format, as returned by
+// String, into the SMBIOS byte order
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, ErrInvalidUUID
+	}
+	b, err := hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	if err != nil {
+		return u, ErrInvalidUUID
+	}
+
+	// First 3 fields are little-endian, last 2 are big-endian
+	u[0], u[1], u[2], u[3] = b[3], b[2], b[1], b[0]
+	u[4], u[5] = b[5], b[4]
+	u[6], u[7] = b[7], b[6]
+	copy(u[8:], b[8:])
+	return u, nil
+}
+
+// Build encodes the system information as an SMBIOS 2.4+ structure with the
+// given handle
+func (si *SystemInfo) Build(handle uint16) (gosmbios.Structure, error) {
+	var st gosmbios.StringTable
+	f := make([]byte, 0x1B)
+	f[0x04] = st.Add(si.Manufacturer)
+	f[0x05] = st.Add(si.ProductName)
+	f[0x06] = st.Add(si.Version)
+	f[0x07] = st.Add(si.SerialNumber)
+	copy(f[0x08:0x18], si.UUID[:])
+	f[0x18] = uint8(si.WakeUpType)
+	f[0x19] = st.Add(si.SKUNumber)
+	f[0x1A] = st.Add(si.Family)
+
+	return gosmbios.NewStructure(StructureType, handle, f[4:], st.Strings())
+}
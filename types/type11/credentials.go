@@ -0,0 +1,225 @@
+package type11
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/earentir/gosmbios"
+)
+
+// systemd credential prefixes, see systemd.system-credentials(7)
+const (
+	CredentialPrefix       = "io.systemd.credential:"
+	CredentialBinaryPrefix = "io.systemd.credential.binary:"
+)
+
+// MaxStrings is the maximum number of strings one OEM Strings structure can hold
+const MaxStrings = 255
+
+// Errors returned when building OEM Strings structures
+var (
+	ErrTooManyStrings  = errors.New("smbios: too many OEM strings for one structure")
+	ErrInvalidString   = errors.New("smbios: OEM string must not be empty or contain NUL")
+	ErrInvalidCredName = errors.New("smbios: invalid credential name")
+)
+
+// EntryKind classifies an OEM string
+type EntryKind int
+
+const (
+	EntryText       EntryKind = iota // Free text
+	EntryKeyValue                    // Generic KEY=VALUE pair
+	EntryCredential                  // systemd credential (text or binary)
+)
+
+// String returns a human-readable entry kind
+func (k EntryKind) String() string {
+	switch k {
+	case EntryText:
+		return "Text"
+	case EntryKeyValue:
+		return "Key/Value"
+	case EntryCredential:
+		return "Credential"
+	default:
+		return "Unknown"
+	}
+}
+
+// Entry is a typed interpretation of a single OEM string
+type Entry struct {
+	Kind   EntryKind
+	Raw    string // Original string
+	Key    string // Key or credential name (empty for text)
+	Value  string // Value as written in the string (base64 text for binary credentials)
+	Data   []byte // Credential payload, base64-decoded for binary credentials
+	Binary bool   // Credential was given in io.systemd.credential.binary form
+	Err    error  // Decoding error for malformed binary credentials
+}
+
+// Credential is a named systemd credential
+type Credential struct {
+	Name string
+	Data []byte
+}
+
+// validCredentialName reports whether name is acceptable to systemd:
+// a non-empty file name without '/' and at most 255 bytes
+func validCredentialName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		len(name) <= 255 && !strings.ContainsAny(name, "/\x00")
+}
+
+// validKey reports whether s looks like a key: non-empty, no white space
+func validKey(s string) bool {
+	return s != "" && !strings.ContainsAny(s, " \t\r\n")
+}
+
+// ParseEntry interprets a single OEM string
+func ParseEntry(s string) Entry {
+	e := Entry{Kind: EntryText, Raw: s}
+
+	switch {
+	case strings.HasPrefix(s, CredentialBinaryPrefix):
+		name, value, ok := strings.Cut(strings.TrimPrefix(s, CredentialBinaryPrefix), "=")
+		if !ok || !validCredentialName(name) {
+			return e
+		}
+		e.Kind = EntryCredential
+		e.Key = name
+		e.Value = value
+		e.Binary = true
+		e.Data, e.Err = base64.StdEncoding.DecodeString(value)
+		return e
+
+	case strings.HasPrefix(s, CredentialPrefix):
+		name, value, ok := strings.Cut(strings.TrimPrefix(s, CredentialPrefix), "=")
+		if !ok || !validCredentialName(name) {
+			return e
+		}
+		e.Kind = EntryCredential
+		e.Key = name
+		e.Value = value
+		e.Data = []byte(value)
+		return e
+	}
+
+	if key, value, ok := strings.Cut(s, "="); ok && validKey(key) {
+		e.Kind = EntryKeyValue
+		e.Key = key
+		e.Value = value
+	}
+	return e
+}
+
+// Entries returns the typed interpretation of each string
+func (o *OEMStrings) Entries() []Entry {
+	entries := make([]Entry, 0, len(o.Strings))
+	for _, s := range o.Strings {
+		entries = append(entries, ParseEntry(s))
+	}
+	return entries
+}
+
+// Credentials returns the well-formed systemd credentials in this structure
+func (o *OEMStrings) Credentials() []Credential {
+	var creds []Credential
+	for _, e := range o.Entries() {
+		if e.Kind == EntryCredential && e.Err == nil {
+			creds = append(creds, Credential{Name: e.Key, Data: e.Data})
+		}
+	}
+	return creds
+}
+
+// KeyValues returns the generic KEY=VALUE entries (excluding credentials).
+// When a key repeats, the last value wins.
+func (o *OEMStrings) KeyValues() map[string]string {
+	kv := make(map[string]string)
+	for _, e := range o.Entries() {
+		if e.Kind == EntryKeyValue {
+			kv[e.Key] = e.Value
+		}
+	}
+	return kv
+}
+
+// GetCredentials returns the systemd credentials from all OEM Strings structures.
+// Like systemd, the first occurrence of a name wins.
+func GetCredentials(sm *gosmbios.SMBIOS) ([]Credential, error) {
+	oems, err := GetAll(sm)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var creds []Credential
+	for _, oem := range oems {
+		for _, c := range oem.Credentials() {
+			if !seen[c.Name] {
+				seen[c.Name] = true
+				creds = append(creds, c)
+			}
+		}
+	}
+	return creds, nil
+}
+
+// CredentialString encodes a credential as an OEM string. Payloads that are
+// valid single-line UTF-8 text use the plain form, anything else the binary
+// (base64) form.
+func CredentialString(c Credential) (string, error) {
+	if !validCredentialName(c.Name) {
+		return "", ErrInvalidCredName
+	}
+	if utf8.Valid(c.Data) && !strings.ContainsAny(string(c.Data), "\x00\r\n") {
+		return CredentialPrefix + c.Name + "=" + string(c.Data), nil
+	}
+	return CredentialBinaryPrefix + c.Name + "=" + base64.StdEncoding.EncodeToString(c.Data), nil
+}
+
+// Build creates an OEM Strings structure holding the given strings
+func Build(handle uint16, strs []string) (gosmbios.Structure, error) {
+	if len(strs) > MaxStrings {
+		return gosmbios.Structure{}, ErrTooManyStrings
+	}
+	for _, s := range strs {
+		if s == "" || strings.ContainsRune(s, 0) {
+			return gosmbios.Structure{}, ErrInvalidString
+		}
+	}
+	return gosmbios.NewStructure(StructureType, handle, []byte{uint8(len(strs))}, strs)
+}
+
+// BuildCredentials creates OEM Strings structures carrying the given
+// credentials, for example to provision a VM. A new structure is started every
+// MaxStrings credentials, with consecutive handles starting at firstHandle.
+func BuildCredentials(firstHandle uint16, creds []Credential) ([]gosmbios.Structure, error) {
+	var strs []string
+	for _, c := range creds {
+		s, err := CredentialString(c)
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, s)
+	}
+
+	var structures []gosmbios.Structure
+	handle := firstHandle
+	for len(strs) > 0 {
+		n := len(strs)
+		if n > MaxStrings {
+			n = MaxStrings
+		}
+		s, err := Build(handle, strs[:n])
+		if err != nil {
+			return nil, err
+		}
+		structures = append(structures, s)
+		strs = strs[n:]
+		handle++
+	}
+	return structures, nil
+}
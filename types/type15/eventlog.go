@@ -221,6 +221,16 @@ const (
 	VarDataSymbol    VariableDataFormat = 0x06
 )
 
+// Variable data formats named as in DSP0134; VarDataPOSTCodes,
+// VarDataTimeStamp, VarDataTime and VarDataSymbol are the same values under
+// their earlier names.
+const (
+	VarDataMultipleHandle           VariableDataFormat = 0x03
+	VarDataPOSTResults              VariableDataFormat = 0x04
+	VarDataSystemManagement         VariableDataFormat = 0x05
+	VarDataMultipleSystemManagement VariableDataFormat = 0x06
+)
+
 func (v VariableDataFormat) String() string {
 	switch v {
 	case VarDataNone:
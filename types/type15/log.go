@@ -0,0 +1,374 @@
+package type15
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Errors returned when decoding the log area
+var (
+	ErrShortLogArea    = errors.New("smbios: event log area is shorter than its offsets")
+	ErrNotMemoryMapped = errors.New("smbios: event log is not memory-mapped")
+)
+
+// LogHeader is the Type 1 log header
+type LogHeader struct {
+	OEMReserved                 [5]byte
+	MultipleEventTimeWindow     uint8 // Minutes
+	MultipleEventCountIncrement uint8
+	PreBootResetCMOSAddress     uint8
+	PreBootResetCMOSBitIndex    uint8
+	CMOSChecksumStartOffset     uint8
+	CMOSChecksumByteCount       uint8
+	CMOSChecksumOffset          uint8
+	Revision                    uint8
+}
+
+// logHeaderType1Length is the length of the Type 1 log header
+const logHeaderType1Length = 16
+
+// Record is a decoded event log record
+type Record struct {
+	Offset int // Offset in the log area
+	Type   EventLogType
+	Length uint8 // Record length including the type and length bytes
+	Read   bool  // The record has been processed by higher-level software (length bit 7 clear)
+	Time   time.Time
+	// TimeValid is false when the BCD timestamp does not decode. Times are
+	// the RTC time of the logging system, stored as UTC.
+	TimeValid bool
+	Format    VariableDataFormat
+	Data      []byte // Variable data
+
+	// Decoded variable data, depending on Format
+	Handle               uint16
+	Counter              uint32 // Multiple-event counter
+	POSTResults          POSTResults
+	SystemManagementType SystemManagementType
+}
+
+// HasHandle returns true if the variable data holds a structure handle
+func (r *Record) HasHandle() bool {
+	return r.Format == VarDataHandle || r.Format == VarDataMultipleHandle
+}
+
+// HasCounter returns true if the variable data holds a multiple-event counter
+func (r *Record) HasCounter() bool {
+	return r.Format == VarDataMultiple || r.Format == VarDataMultipleHandle || r.Format == VarDataMultipleSystemManagement
+}
+
+func (r *Record) String() string {
+	var b strings.Builder
+	if r.TimeValid {
+		b.WriteString(r.Time.Format("2006-01-02 15:04:05"))
+	} else {
+		b.WriteString("????-??-?? ??:??:??")
+	}
+	b.WriteString(" " + r.Type.String())
+
+	var details []string
+	if r.HasHandle() {
+		details = append(details, fmt.Sprintf("handle 0x%04X", r.Handle))
+	}
+	switch r.Format {
+	case VarDataPOSTResults:
+		details = append(details, r.POSTResults.String())
+	case VarDataSystemManagement, VarDataMultipleSystemManagement:
+		details = append(details, r.SystemManagementType.String())
+	}
+	if r.HasCounter() {
+		details = append(details, fmt.Sprintf("count %d", r.Counter))
+	}
+	if len(details) > 0 {
+		b.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+	return b.String()
+}
+
+// Log is a decoded event log area
+type Log struct {
+	Header      *LogHeader // Nil unless the header format is Type 1
+	Records     []Record
+	ChangeToken uint32 // The Log Change Token the area was read under
+}
+
+// Stale returns true if the log has changed since it was read, as shown by
+// the Log Change Token of a freshly read structure. Read the structure
+// again after reading the area and re-read the area when this is true.
+func (lg *Log) Stale(current *SystemEventLog) bool {
+	return current.LogChangeToken != lg.ChangeToken
+}
+
+// DecodeLog decodes the log area, the LogAreaLength bytes found at the
+// access method address. Offsets in the structure are relative to the start
+// of the area. Records are read from LogDataStartOffset up to the end-of-log
+// record or the end of the area, and their variable data is decoded using
+// the supported event log type descriptors.
+func (l *SystemEventLog) DecodeLog(area []byte) (*Log, error) {
+	if int(l.LogAreaLength) > 0 && len(area) > int(l.LogAreaLength) {
+		area = area[:l.LogAreaLength]
+	}
+	if int(l.LogDataStartOffset) > len(area) {
+		return nil, ErrShortLogArea
+	}
+
+	lg := &Log{ChangeToken: l.LogChangeToken}
+	if l.LogHeaderFormat == LogHeaderType1 {
+		start := int(l.LogHeaderStartOffset)
+		if start+logHeaderType1Length > len(area) {
+			return nil, ErrShortLogArea
+		}
+		h := area[start : start+logHeaderType1Length]
+		lg.Header = &LogHeader{
+			MultipleEventTimeWindow:     h[0x05],
+			MultipleEventCountIncrement: h[0x06],
+			PreBootResetCMOSAddress:     h[0x07],
+			PreBootResetCMOSBitIndex:    h[0x08],
+			CMOSChecksumStartOffset:     h[0x09],
+			CMOSChecksumByteCount:       h[0x0A],
+			CMOSChecksumOffset:          h[0x0B],
+			Revision:                    h[0x0F],
+		}
+		copy(lg.Header.OEMReserved[:], h[0x00:0x05])
+	}
+
+	formats := make(map[EventLogType]VariableDataFormat, len(l.SupportedEventLogTypes))
+	for _, d := range l.SupportedEventLogTypes {
+		formats[d.LogType] = d.VariableDataFormat
+	}
+
+	for offset := int(l.LogDataStartOffset); offset+2 <= len(area); {
+		typ := EventLogType(area[offset])
+		length := area[offset+1] & 0x7F
+		// End of log, unused (erased) space, or a corrupt length
+		if typ == EventLogEndOfLog || length < 8 || offset+int(length) > len(area) {
+			break
+		}
+		lg.Records = append(lg.Records, decodeRecord(area[offset:offset+int(length)], offset, formats[typ]))
+		offset += int(length)
+	}
+	return lg, nil
+}
+
+// decodeRecord decodes one record
+func decodeRecord(b []byte, offset int, format VariableDataFormat) Record {
+	r := Record{
+		Offset: offset,
+		Type:   EventLogType(b[0]),
+		Length: b[1] & 0x7F,
+		Read:   b[1]&0x80 == 0, // The length MSB is set until the record is read
+		Format: format,
+		Data:   append([]byte(nil), b[8:]...),
+	}
+	r.Time, r.TimeValid = bcdTime(b[2:8])
+
+	d := r.Data
+	switch format {
+	case VarDataHandle:
+		if len(d) >= 2 {
+			r.Handle = binary.LittleEndian.Uint16(d)
+		}
+	case VarDataMultiple:
+		if len(d) >= 4 {
+			r.Counter = binary.LittleEndian.Uint32(d)
+		}
+	case VarDataMultipleHandle:
+		if len(d) >= 6 {
+			r.Handle = binary.LittleEndian.Uint16(d)
+			r.Counter = binary.LittleEndian.Uint32(d[2:])
+		}
+	case VarDataPOSTResults:
+		if len(d) >= 8 {
+			r.POSTResults = POSTResults(binary.LittleEndian.Uint64(d))
+		}
+	case VarDataSystemManagement:
+		if len(d) >= 4 {
+			r.SystemManagementType = SystemManagementType(binary.LittleEndian.Uint32(d))
+		}
+	case VarDataMultipleSystemManagement:
+		if len(d) >= 8 {
+			r.SystemManagementType = SystemManagementType(binary.LittleEndian.Uint32(d))
+			r.Counter = binary.LittleEndian.Uint32(d[4:])
+		}
+	}
+	return r
+}
+
+// bcd decodes a BCD byte
+func bcd(v byte) (int, bool) {
+	hi, lo := v>>4, v&0x0F
+	if hi > 9 || lo > 9 {
+		return 0, false
+	}
+	return int(hi)*10 + int(lo), true
+}
+
+// bcdTime decodes the year, month, day, hour, minute and second BCD bytes.
+// Years 80-99 are 1980-1999 and 00-79 are 2000-2079.
+func bcdTime(b []byte) (time.Time, bool) {
+	var v [6]int
+	for i := range v {
+		n, ok := bcd(b[i])
+		if !ok {
+			return time.Time{}, false
+		}
+		v[i] = n
+	}
+	year := 2000 + v[0]
+	if v[0] >= 80 {
+		year = 1900 + v[0]
+	}
+	if v[1] < 1 || v[1] > 12 || v[2] < 1 || v[2] > 31 || v[3] > 23 || v[4] > 59 || v[5] > 59 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(v[1]), v[2], v[3], v[4], v[5], 0, time.UTC), true
+}
+
+// POSTResults is the POST results bitmap, the first DWORD in the low 32 bits
+type POSTResults uint64
+
+// postResultNames names the bits of the POST results bitmap
+var postResultNames = map[uint]string{
+	0:       "Channel 2 timer error",
+	1:       "Master PIC (8259 #1) error",
+	2:       "Slave PIC (8259 #2) error",
+	3:       "CMOS battery failure",
+	4:       "CMOS system options not set",
+	5:       "CMOS checksum error",
+	6:       "CMOS configuration error",
+	7:       "Mouse and keyboard swapped",
+	8:       "Keyboard locked",
+	9:       "Keyboard not functional",
+	10:      "Keyboard controller not functional",
+	11:      "CMOS memory size different",
+	12:      "Memory decreased in size",
+	13:      "Cache memory error",
+	14:      "Floppy drive 0 error",
+	15:      "Floppy drive 1 error",
+	16:      "Floppy controller failure",
+	17:      "Number of ATA drives reduced",
+	18:      "CMOS time not set",
+	19:      "DDC monitor configuration change",
+	32 + 7:  "PCI memory conflict",
+	32 + 8:  "PCI I/O conflict",
+	32 + 9:  "PCI IRQ conflict",
+	32 + 10: "PNP memory conflict",
+	32 + 11: "PNP 32-bit memory conflict",
+	32 + 12: "PNP I/O conflict",
+	32 + 13: "PNP IRQ conflict",
+	32 + 14: "PNP DMA conflict",
+	32 + 15: "Bad PNP serial ID checksum",
+	32 + 16: "Bad PNP resource data checksum",
+	32 + 17: "Static resource conflict",
+	32 + 18: "NVRAM checksum error, system devices disabled",
+	32 + 19: "System board device resource conflict",
+	32 + 20: "Primary output device not found",
+	32 + 21: "Primary input device not found",
+	32 + 22: "Primary boot device not found",
+	32 + 23: "NVRAM cleared by jumper",
+	32 + 24: "NVRAM data invalid, NVRAM cleared",
+	32 + 25: "FDC resource conflict",
+	32 + 26: "Primary ATA controller resource conflict",
+	32 + 27: "Secondary ATA controller resource conflict",
+	32 + 28: "Parallel port resource conflict",
+	32 + 29: "Serial port 1 resource conflict",
+	32 + 30: "Serial port 2 resource conflict",
+	32 + 31: "Audio resource conflict",
+}
+
+// Errors returns the names of the set bits
+func (p POSTResults) Errors() []string {
+	var names []string
+	for bit := uint(0); bit < 64; bit++ {
+		if p&(1<<bit) == 0 {
+			continue
+		}
+		if name, ok := postResultNames[bit]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("DWORD %d bit %d", bit/32+1, bit%32))
+		}
+	}
+	return names
+}
+
+func (p POSTResults) String() string {
+	if p == 0 {
+		return "No POST errors"
+	}
+	return strings.Join(p.Errors(), ", ")
+}
+
+// SystemManagementType identifies a system management event
+type SystemManagementType uint32
+
+// systemManagementNames are the DSP0134 system management types; the gaps
+// are reserved
+var systemManagementNames = map[SystemManagementType]string{
+	0x00: "+2.5V out of range, #1",
+	0x01: "+2.5V out of range, #2",
+	0x02: "+3.3V out of range",
+	0x03: "+5V out of range",
+	0x04: "-5V out of range",
+	0x05: "+12V out of range",
+	0x06: "-12V out of range",
+	0x10: "System board temperature out of range",
+	0x11: "Processor #1 temperature out of range",
+	0x12: "Processor #2 temperature out of range",
+	0x13: "Processor #3 temperature out of range",
+	0x14: "Processor #4 temperature out of range",
+	0x30: "Chassis secure switch activated",
+}
+
+func (t SystemManagementType) String() string {
+	if name, ok := systemManagementNames[t]; ok {
+		return name
+	}
+	switch {
+	case t >= 0x20 && t <= 0x27:
+		return fmt.Sprintf("Fan %d out of range", uint32(t)-0x20)
+	case t >= 0x10000 && t <= 0x1FFFF:
+		return fmt.Sprintf("Probe or cooling device out of range (handle 0x%04X)", uint16(t))
+	case t >= 0x20000 && t <= 0x2FFFF:
+		return fmt.Sprintf("Probe or cooling device back in range (handle 0x%04X)", uint16(t))
+	case t >= 0x80000000:
+		return fmt.Sprintf("OEM-specific (0x%08X)", uint32(t))
+	}
+	return fmt.Sprintf("System management type 0x%08X", uint32(t))
+}
+
+// IndexPort returns the index port of indexed I/O access methods
+func (l *SystemEventLog) IndexPort() uint16 {
+	return uint16(l.AccessMethodAddress)
+}
+
+// DataPort returns the data port of indexed I/O access methods
+func (l *SystemEventLog) DataPort() uint16 {
+	return uint16(l.AccessMethodAddress >> 16)
+}
+
+// GPNVHandle returns the handle of the GPNV area of the GPNV access method
+func (l *SystemEventLog) GPNVHandle() uint16 {
+	return uint16(l.AccessMethodAddress)
+}
+
+// ReadLogArea reads a memory-mapped log area from a physical memory image
+// whose first byte is at physical address base
+func (l *SystemEventLog) ReadLogArea(image io.ReaderAt, base uint64) ([]byte, error) {
+	if l.AccessMethod != AccessMemoryMapped32Bit {
+		return nil, ErrNotMemoryMapped
+	}
+	addr := uint64(l.AccessMethodAddress)
+	if addr < base {
+		return nil, ErrShortLogArea
+	}
+	area := make([]byte, l.LogAreaLength)
+	if _, err := image.ReadAt(area, int64(addr-base)); err != nil {
+		return nil, err
+	}
+	return area, nil
+}
@@ -0,0 +1,136 @@
+package type17
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JEDECLabel returns the industry-standard module description, such as
+// "32GB 2R PC5-4800 RDIMM" or "16GB 1R DDR4-3200 SO-DIMM", giving one key
+// to compare modules across vendors. Registered and load-reduced modules
+// are named by their JEDEC PC module name and others by chip type and
+// speed, followed by the module type. SMBIOS does not record the DRAM
+// device width (x4, x8), CAS latency grade or raw card of the full JEDEC
+// label, so they are left out. It returns an empty string for empty slots.
+func (m *MemoryDevice) JEDECLabel() string {
+	if !m.IsPopulated() {
+		return ""
+	}
+
+	parts := []string{labelSize(m.Size)}
+	if ranks := m.Ranks(); ranks > 0 {
+		parts = append(parts, fmt.Sprintf("%dR", ranks))
+	}
+
+	buffered := m.TypeDetail.Has(TypeDetailRegistered) || m.TypeDetail.Has(TypeDetailLRDIMM)
+	if name := m.pcName(); buffered && name != "" {
+		parts = append(parts, name)
+	} else if speed := m.GetSpeed(); speed > 0 {
+		parts = append(parts, fmt.Sprintf("%s-%d", m.MemoryType, speed))
+	} else {
+		parts = append(parts, m.MemoryType.String())
+	}
+
+	if moduleType := m.moduleType(); moduleType != "" {
+		parts = append(parts, moduleType)
+	}
+	return strings.Join(parts, " ")
+}
+
+// labelSize formats a size in MB as on module labels, e.g. "32GB"
+func labelSize(mb uint64) string {
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%dGB", mb/1024)
+	}
+	return fmt.Sprintf("%dMB", mb)
+}
+
+// pcBandwidth maps DDR, DDR2 and DDR3 speed grades in MT/s to the peak
+// bandwidth in MB/s of their JEDEC PC module names, which is rounded rather
+// than eight times the speed, e.g. DDR3-1333 is PC3-10600
+var pcBandwidth = map[uint32]uint32{
+	200:  1600,
+	266:  2100,
+	333:  2700,
+	400:  3200,
+	533:  4200,
+	667:  5300,
+	800:  6400,
+	1066: 8500,
+	1333: 10600,
+	1600: 12800,
+	1866: 14900,
+	2133: 17000,
+}
+
+// bandwidth returns the PC name bandwidth of a speed grade, allowing for
+// firmware that rounds the speed the other way (1067 for 1066), or 0 if the
+// speed is not a JEDEC grade
+func bandwidth(speed uint32) uint32 {
+	for _, s := range []uint32{speed, speed - 1, speed + 1} {
+		if bw, ok := pcBandwidth[s]; ok {
+			return bw
+		}
+	}
+	return 0
+}
+
+// pcName returns the JEDEC PC module name, e.g. "PC4-3200" or "PC3L-12800",
+// or an empty string if the type has none or the speed is unknown. Before
+// DDR4 the name gives the peak bandwidth in MB/s rather than the data rate.
+func (m *MemoryDevice) pcName() string {
+	speed := m.GetSpeed()
+	if speed == 0 {
+		return ""
+	}
+	switch m.MemoryType {
+	case MemTypeDDR4:
+		return fmt.Sprintf("PC4-%d", speed)
+	case MemTypeDDR5:
+		return fmt.Sprintf("PC5-%d", speed)
+	}
+
+	bw := bandwidth(speed)
+	if bw == 0 {
+		return ""
+	}
+	switch m.MemoryType {
+	case MemTypeDDR:
+		return fmt.Sprintf("PC%d", bw)
+	case MemTypeDDR2, MemTypeDDR2FBDIMM:
+		return fmt.Sprintf("PC2-%d", bw)
+	case MemTypeDDR3:
+		if m.MinimumVoltage == 1350 {
+			return fmt.Sprintf("PC3L-%d", bw)
+		}
+		return fmt.Sprintf("PC3-%d", bw)
+	}
+	return ""
+}
+
+// moduleType returns the module type of the label, e.g. "RDIMM",
+// "ECC UDIMM" or "SO-DIMM"
+func (m *MemoryDevice) moduleType() string {
+	ecc := m.TotalWidth != 0xFFFF && m.DataWidth != 0xFFFF && m.TotalWidth > m.DataWidth
+	var name string
+	switch {
+	case m.TypeDetail.Has(TypeDetailLRDIMM):
+		return "LRDIMM"
+	case m.TypeDetail.Has(TypeDetailRegistered):
+		return "RDIMM"
+	case m.FormFactor == FormFactorDIMM:
+		name = "UDIMM"
+	case m.FormFactor == FormFactorSODIMM:
+		name = "SO-DIMM"
+	case m.FormFactor == FormFactorFBDIMM:
+		return "FB-DIMM"
+	case m.FormFactor == FormFactorOther || m.FormFactor == FormFactorUnknown || m.FormFactor == 0:
+		return ""
+	default:
+		name = m.FormFactor.String()
+	}
+	if ecc {
+		return "ECC " + name
+	}
+	return name
+}
@@ -0,0 +1,74 @@
+package type17
+
+import (
+	"fmt"
+
+	"github.com/earentir/gosmbios/jep106"
+)
+
+// ModuleManufacturer returns the module manufacturer from the JEP-106 code
+// in ModuleManufacturerID, or an empty string if it is not reported
+func (m *MemoryDevice) ModuleManufacturer() string {
+	return jep106.Lookup(m.ModuleManufacturerID)
+}
+
+// MemorySubsystemControllerManufacturer returns the manufacturer of the
+// memory subsystem controller, such as an NVDIMM controller, or an empty
+// string if it is not reported
+func (m *MemoryDevice) MemorySubsystemControllerManufacturer() string {
+	return jep106.Lookup(m.MemorySubsystemControllerManufacturerID)
+}
+
+// PMIC0Manufacturer returns the manufacturer of the power management IC,
+// or an empty string if it is not reported
+func (m *MemoryDevice) PMIC0Manufacturer() string {
+	return jep106.Lookup(m.PMIC0ManufacturerID)
+}
+
+// RCDManufacturer returns the manufacturer of the registering clock driver,
+// or an empty string if it is not reported
+func (m *MemoryDevice) RCDManufacturer() string {
+	return jep106.Lookup(m.RCDManufacturerID)
+}
+
+// Vendor returns the canonical module vendor: the name decoded from
+// ModuleManufacturerID when known, and the normalized Manufacturer string
+// otherwise, so "80CE", "Samsung" and "Samsung Electronics" all give
+// "Samsung"
+func (m *MemoryDevice) Vendor() string {
+	if c, ok := jep106.FromWord(m.ModuleManufacturerID); ok && c.Name() != "" {
+		return c.Name()
+	}
+	return jep106.Normalize(m.Manufacturer)
+}
+
+// PMIC0RevisionString returns the PMIC revision, e.g. "1.2", or an empty
+// string before SMBIOS 3.7
+func (m *MemoryDevice) PMIC0RevisionString() string {
+	return m.componentRevision(m.PMIC0RevisionNumber)
+}
+
+// RCDRevisionString returns the RCD revision, e.g. "1.2", or an empty
+// string before SMBIOS 3.7
+func (m *MemoryDevice) RCDRevisionString() string {
+	return m.componentRevision(m.RCDRevisionNumber)
+}
+
+// componentRevision decodes a PMIC or RCD revision number. DDR5 and
+// LPDDR5 SPD store the major revision in bits 7:4 and the minor in bits
+// 3:0; for other memory types the value is shown as is.
+func (m *MemoryDevice) componentRevision(rev uint16) string {
+	switch {
+	case m.Header.Length < 100:
+		return ""
+	case rev == 0xFF00:
+		return "Unknown"
+	case m.MemoryType == MemTypeDDR5 || m.MemoryType == MemTypeLPDDR5:
+		if rev&0xFF00 != 0 {
+			return fmt.Sprintf("Invalid (0x%04X)", rev)
+		}
+		return fmt.Sprintf("%X.%X", rev>>4&0x0F, rev&0x0F)
+	default:
+		return fmt.Sprintf("0x%04X", rev)
+	}
+}
@@ -526,17 +526,19 @@ func (m *MemoryDevice) SpeedString() string {
 	return fmt.Sprintf("%d MT/s", speed)
 }
 
-// GetSpeed returns the effective speed in MT/s
+// GetSpeed returns the effective speed in MT/s, or 0 if it is unknown.
+// 0xFFFF defers to the extended speed, so it is unknown without one.
 func (m *MemoryDevice) GetSpeed() uint32 {
-	if m.ExtendedSpeed > 0 && m.Speed == 0xFFFF {
+	if m.Speed == 0xFFFF {
 		return m.ExtendedSpeed
 	}
 	return uint32(m.Speed)
 }
 
-// GetConfiguredSpeed returns the configured speed in MT/s
+// GetConfiguredSpeed returns the configured speed in MT/s, or 0 if it is
+// unknown
 func (m *MemoryDevice) GetConfiguredSpeed() uint32 {
-	if m.ExtendedConfiguredMemorySpeed > 0 && m.ConfiguredMemorySpeed == 0xFFFF {
+	if m.ConfiguredMemorySpeed == 0xFFFF {
 		return m.ExtendedConfiguredMemorySpeed
 	}
 	return uint32(m.ConfiguredMemorySpeed)
@@ -572,3 +574,16 @@ func (m *MemoryDevice) DisplayName() string {
 		m.SizeString(),
 		m.SpeedString())
 }
+
+// Sanitized returns a copy with placeholder identity fields (such as
+// "SerNum0" or "Not Specified") replaced by empty strings.
+// DeviceLocator and BankLocator are kept as-is since they identify the slot.
+func (m *MemoryDevice) Sanitized() *MemoryDevice {
+	c := *m
+	c.Manufacturer = gosmbios.Sanitize(m.Manufacturer)
+	c.SerialNumber = gosmbios.Sanitize(m.SerialNumber)
+	c.AssetTag = gosmbios.Sanitize(m.AssetTag)
+	c.PartNumber = gosmbios.Sanitize(m.PartNumber)
+	c.FirmwareVersion = gosmbios.Sanitize(m.FirmwareVersion)
+	return &c
+}
@@ -3,6 +3,8 @@
 package type2
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/earentir/gosmbios"
@@ -204,3 +206,50 @@ func (b *BaseboardInfo) DisplayName() string {
 	}
 	return "Unknown Baseboard"
 }
+
+// Sanitized returns a copy with placeholder identity fields (such as
+// "Default string" or "Base Board Serial Number") replaced by empty strings
+func (b *BaseboardInfo) Sanitized() *BaseboardInfo {
+	c := *b
+	c.Manufacturer = gosmbios.Sanitize(b.Manufacturer)
+	c.Product = gosmbios.Sanitize(b.Product)
+	c.Version = gosmbios.Sanitize(b.Version)
+	c.SerialNumber = gosmbios.Sanitize(b.SerialNumber)
+	c.AssetTag = gosmbios.Sanitize(b.AssetTag)
+	return &c
+}
+
+// MaxContainedObjectHandles is the most contained object handles that fit
+// the 255-byte structure length
+const MaxContainedObjectHandles = (255 - 0x0F) / 2
+
+// ErrTooManyHandles is returned when building a structure with more than
+// MaxContainedObjectHandles contained object handles
+var ErrTooManyHandles = errors.New("smbios: too many contained object handles for one baseboard structure")
+
+// Build encodes the baseboard information as an SMBIOS structure with the
+// given handle
+func (b *BaseboardInfo) Build(handle uint16) (gosmbios.Structure, error) {
+	var st gosmbios.StringTable
+	handles := b.ContainedObjectHandles
+	if len(handles) > MaxContainedObjectHandles {
+		return gosmbios.Structure{}, ErrTooManyHandles
+	}
+
+	f := make([]byte, 0x0F+len(handles)*2)
+	f[0x04] = st.Add(b.Manufacturer)
+	f[0x05] = st.Add(b.Product)
+	f[0x06] = st.Add(b.Version)
+	f[0x07] = st.Add(b.SerialNumber)
+	f[0x08] = st.Add(b.AssetTag)
+	f[0x09] = uint8(b.FeatureFlags)
+	f[0x0A] = st.Add(b.LocationInChassis)
+	binary.LittleEndian.PutUint16(f[0x0B:], b.ChassisHandle)
+	f[0x0D] = uint8(b.BoardType)
+	f[0x0E] = uint8(len(handles))
+	for i, h := range handles {
+		binary.LittleEndian.PutUint16(f[0x0F+i*2:], h)
+	}
+
+	return gosmbios.NewStructure(StructureType, handle, f[4:], st.Strings())
+}
@@ -3,6 +3,8 @@
 package type3
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/earentir/gosmbios"
@@ -294,11 +296,12 @@ func Parse(s *gosmbios.Structure) (*ChassisInfo, error) {
 					offset += int(elementRecordLen)
 				}
 			}
+		}
 
-			// SKU Number (SMBIOS 2.7+) - follows contained elements
-			if offset < len(s.Data) {
-				info.SKUNumber = s.GetString(s.GetByte(offset))
-			}
+		// SKU Number (SMBIOS 2.7+) - follows contained elements, which may be absent
+		skuOffset := 0x15 + int(containedCount)*int(elementRecordLen)
+		if skuOffset < len(s.Data) {
+			info.SKUNumber = s.GetString(s.GetByte(skuOffset))
 		}
 	}
 
@@ -342,3 +345,61 @@ func (c *ChassisInfo) HeightString() string {
 	}
 	return fmt.Sprintf("%dU", c.Height)
 }
+
+// Sanitized returns a copy with placeholder identity fields (such as
+// "Chassis Serial Number" or "No Asset Tag") replaced by empty strings
+func (c *ChassisInfo) Sanitized() *ChassisInfo {
+	s := *c
+	s.Manufacturer = gosmbios.Sanitize(c.Manufacturer)
+	s.Version = gosmbios.Sanitize(c.Version)
+	s.SerialNumber = gosmbios.Sanitize(c.SerialNumber)
+	s.AssetTag = gosmbios.Sanitize(c.AssetTag)
+	s.SKUNumber = gosmbios.Sanitize(c.SKUNumber)
+	return &s
+}
+
+// MaxContainedElements is the most 3-byte contained element records that
+// fit the 255-byte structure length alongside the SKU Number
+const MaxContainedElements = (255 - 0x15 - 1) / 3
+
+// ErrTooManyElements is returned when building a structure with more than
+// MaxContainedElements contained elements
+var ErrTooManyElements = errors.New("smbios: too many contained elements for one chassis structure")
+
+// Build encodes the chassis information as an SMBIOS 2.7+ structure with the
+// given handle. Contained elements use 3-byte records.
+func (c *ChassisInfo) Build(handle uint16) (gosmbios.Structure, error) {
+	var st gosmbios.StringTable
+	n := len(c.ContainedElements)
+	if n > MaxContainedElements {
+		return gosmbios.Structure{}, ErrTooManyElements
+	}
+
+	f := make([]byte, 0x15+n*3+1)
+	f[0x04] = st.Add(c.Manufacturer)
+	f[0x05] = uint8(c.Type) & 0x7F
+	if c.TypeLocked {
+		f[0x05] |= 0x80
+	}
+	f[0x06] = st.Add(c.Version)
+	f[0x07] = st.Add(c.SerialNumber)
+	f[0x08] = st.Add(c.AssetTag)
+	f[0x09] = uint8(c.BootUpState)
+	f[0x0A] = uint8(c.PowerSupplyState)
+	f[0x0B] = uint8(c.ThermalState)
+	f[0x0C] = uint8(c.SecurityStatus)
+	binary.LittleEndian.PutUint32(f[0x0D:], c.OEMDefined)
+	f[0x11] = c.Height
+	f[0x12] = c.NumberOfPowerCords
+	f[0x13] = uint8(n)
+	f[0x14] = 3
+	for i, e := range c.ContainedElements {
+		off := 0x15 + i*3
+		f[off] = e.Type
+		f[off+1] = e.Minimum
+		f[off+2] = e.Maximum
+	}
+	f[0x15+n*3] = st.Add(c.SKUNumber)
+
+	return gosmbios.NewStructure(StructureType, handle, f[4:], st.Strings())
+}
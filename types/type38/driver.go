@@ -0,0 +1,185 @@
+package type38
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned when generating driver configuration
+var (
+	ErrUnsupportedInterface = errors.New("smbios: unsupported IPMI interface type")
+	ErrNoBaseAddress        = errors.New("smbios: IPMI base address not specified")
+)
+
+// IsIOSpace returns true if the base address is in I/O space rather than
+// memory-mapped, as flagged by bit 0 of the Base Address
+func (i *IPMIDeviceInfo) IsIOSpace() bool {
+	return i.BaseAddress&0x01 != 0
+}
+
+// Address returns the effective base address of a KCS, SMIC or BT
+// interface: the Base Address with bit 0 replaced by the LS-bit of the
+// Base Address Modifier
+func (i *IPMIDeviceInfo) Address() uint64 {
+	addr := i.BaseAddress &^ 1
+	if i.BaseAddressModifier.IsLSBit() {
+		addr |= 1
+	}
+	return addr
+}
+
+// SMBusAddress returns the 7-bit SMBus address of an SSIF interface, which
+// the Base Address holds in 8-bit form
+func (i *IPMIDeviceInfo) SMBusAddress() uint8 {
+	return uint8(i.BaseAddress) >> 1
+}
+
+// RegisterSpacingBytes returns the register spacing in bytes, or 0 if it is
+// reserved
+func (i *IPMIDeviceInfo) RegisterSpacingBytes() int {
+	switch i.BaseAddressModifier.RegisterSpacing() {
+	case RegisterSpacingByte:
+		return 1
+	case RegisterSpacing4Byte:
+		return 4
+	case RegisterSpacing16Byte:
+		return 16
+	}
+	return 0
+}
+
+// Interrupt returns the interrupt number, or false if the interface does not
+// use an interrupt or the interrupt information is not specified
+func (i *IPMIDeviceInfo) Interrupt() (uint8, bool) {
+	if i.InterruptNumber == 0 || !i.BaseAddressModifier.InterruptEnabled() {
+		return 0, false
+	}
+	return i.InterruptNumber, true
+}
+
+// Module returns the Linux kernel driver for the interface, ipmi_si or
+// ipmi_ssif
+func (i *IPMIDeviceInfo) Module() (string, error) {
+	switch i.InterfaceType {
+	case InterfaceTypeKCS, InterfaceTypeSMIC, InterfaceTypeBT:
+		return "ipmi_si", nil
+	case InterfaceTypeSSIF:
+		return "ipmi_ssif", nil
+	}
+	return "", ErrUnsupportedInterface
+}
+
+// moduleParameters returns the driver and its parameters in order
+func (i *IPMIDeviceInfo) moduleParameters() (string, [][2]string, error) {
+	module, err := i.Module()
+	if err != nil {
+		return "", nil, err
+	}
+	if i.BaseAddress == 0 {
+		return "", nil, ErrNoBaseAddress
+	}
+
+	var params [][2]string
+	if i.InterfaceType == InterfaceTypeSSIF {
+		params = append(params, [2]string{"addr", fmt.Sprintf("0x%02x", i.SMBusAddress())})
+	} else {
+		params = append(params, [2]string{"type", strings.ToLower(i.InterfaceType.shortName())})
+		key := "addrs"
+		if i.IsIOSpace() {
+			key = "ports"
+		}
+		params = append(params, [2]string{key, fmt.Sprintf("0x%x", i.Address())})
+		if spacing := i.RegisterSpacingBytes(); spacing > 0 {
+			params = append(params, [2]string{"regspacings", fmt.Sprintf("%d", spacing)})
+		}
+		irq, _ := i.Interrupt()
+		params = append(params, [2]string{"irqs", fmt.Sprintf("%d", irq)})
+	}
+	if i.I2CSlaveAddress != 0 {
+		params = append(params, [2]string{"slave_addrs", fmt.Sprintf("0x%02x", i.I2CSlaveAddress)})
+	}
+	return module, params, nil
+}
+
+// shortName returns the interface name as used by drivers
+func (t InterfaceType) shortName() string {
+	switch t {
+	case InterfaceTypeKCS:
+		return "KCS"
+	case InterfaceTypeSMIC:
+		return "SMIC"
+	case InterfaceTypeBT:
+		return "BT"
+	case InterfaceTypeSSIF:
+		return "SSIF"
+	}
+	return ""
+}
+
+// ModprobeOptions returns an /etc/modprobe.d line that loads the OpenIPMI
+// kernel driver at the address given in the table, e.g.
+// "options ipmi_si type=kcs ports=0xca2 regspacings=1 irqs=0"
+func (i *IPMIDeviceInfo) ModprobeOptions() (string, error) {
+	module, params, err := i.moduleParameters()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("options " + module)
+	for _, p := range params {
+		fmt.Fprintf(&b, " %s=%s", p[0], p[1])
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// KernelParameters returns the same settings as kernel command line
+// parameters, for drivers built into the kernel, e.g.
+// "ipmi_si.type=kcs ipmi_si.ports=0xca2 ipmi_si.regspacings=1 ipmi_si.irqs=0"
+func (i *IPMIDeviceInfo) KernelParameters() (string, error) {
+	module, params, err := i.moduleParameters()
+	if err != nil {
+		return "", err
+	}
+	args := make([]string, len(params))
+	for n, p := range params {
+		args[n] = fmt.Sprintf("%s.%s=%s", module, p[0], p[1])
+	}
+	return strings.Join(args, " "), nil
+}
+
+// FreeIPMIConfig returns a freeipmi.conf snippet that disables probing and
+// uses the interface given in the table. FreeIPMI drives KCS and SSIF
+// directly; SMIC and BT interfaces are used through the OpenIPMI driver, so
+// ipmi_si must be loaded as given by ModprobeOptions.
+func (i *IPMIDeviceInfo) FreeIPMIConfig() (string, error) {
+	if _, err := i.Module(); err != nil {
+		return "", err
+	}
+	if i.BaseAddress == 0 {
+		return "", ErrNoBaseAddress
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s interface, IPMI %s\n", i.InterfaceType.shortName(), i.SpecificationRevisionString())
+	switch i.InterfaceType {
+	case InterfaceTypeKCS:
+		b.WriteString("driver-type KCS\n")
+		b.WriteString("disable-auto-probe\n")
+		fmt.Fprintf(&b, "driver-address 0x%x\n", i.Address())
+		if spacing := i.RegisterSpacingBytes(); spacing > 0 {
+			fmt.Fprintf(&b, "register-spacing %d\n", spacing)
+		}
+	case InterfaceTypeSSIF:
+		b.WriteString("# Set driver-device to the i2c-dev node of the SMBus the BMC is on\n")
+		b.WriteString("driver-type SSIF\n")
+		b.WriteString("disable-auto-probe\n")
+		fmt.Fprintf(&b, "driver-address 0x%02x\n", i.SMBusAddress())
+		b.WriteString("driver-device /dev/i2c-0\n")
+	default:
+		b.WriteString("driver-type OPENIPMI\n")
+		b.WriteString("driver-device /dev/ipmi0\n")
+	}
+	return b.String(), nil
+}
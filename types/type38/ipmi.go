@@ -59,12 +59,16 @@ func (b BaseAddressModifier) RegisterSpacing() RegisterSpacing {
 	return RegisterSpacing((b >> 6) & 0x03)
 }
 
-// IsLSBit returns true if the LSB of base address is zero
+// IsLSBit returns the value of bit 0 of the base address, which the Base
+// Address field itself uses as the I/O space flag
 func (b BaseAddressModifier) IsLSBit() bool {
 	return b&0x10 != 0
 }
 
-// IsIOSpace returns true if the address is in I/O space (vs memory space)
+// IsIOSpace reads bit 0 of the modifier.
+//
+// Deprecated: bit 0 of the modifier is the interrupt trigger mode; the
+// address space is bit 0 of the Base Address, see IPMIDeviceInfo.IsIOSpace.
 func (b BaseAddressModifier) IsIOSpace() bool {
 	return b&0x01 != 0
 }
@@ -76,21 +80,27 @@ func (b BaseAddressModifier) InterruptPolarity() bool {
 
 // InterruptTriggerMode returns true if level triggered
 func (b BaseAddressModifier) InterruptTriggerMode() bool {
-	return b&0x04 != 0
+	return b&0x01 != 0
 }
 
-// InterruptEnabled returns true if interrupt generation is enabled
+// InterruptEnabled returns true if the interrupt information is specified
 func (b BaseAddressModifier) InterruptEnabled() bool {
 	return b&0x08 != 0
 }
 
 func (b BaseAddressModifier) String() string {
-	space := "Memory"
-	if b.IsIOSpace() {
-		space = "I/O"
+	s := fmt.Sprintf("Register Spacing: %s", b.RegisterSpacing().String())
+	if b.InterruptEnabled() {
+		polarity, trigger := "Active Low", "Edge"
+		if b.InterruptPolarity() {
+			polarity = "Active High"
+		}
+		if b.InterruptTriggerMode() {
+			trigger = "Level"
+		}
+		s += fmt.Sprintf(", Interrupt: %s, %s", polarity, trigger)
 	}
-	return fmt.Sprintf("Address Space: %s, Register Spacing: %s",
-		space, b.RegisterSpacing().String())
+	return s
 }
 
 // RegisterSpacing identifies register spacing
@@ -167,12 +177,15 @@ func (i *IPMIDeviceInfo) I2CAddressString() string {
 	return fmt.Sprintf("0x%02X", i.I2CSlaveAddress>>1)
 }
 
-// BaseAddressString returns the base address as a string
+// BaseAddressString returns the effective base address as a string
 func (i *IPMIDeviceInfo) BaseAddressString() string {
-	if i.BaseAddressModifier.IsIOSpace() {
-		return fmt.Sprintf("I/O 0x%04X", i.BaseAddress)
+	if i.InterfaceType == InterfaceTypeSSIF {
+		return fmt.Sprintf("SMBus 0x%02X", i.SMBusAddress())
+	}
+	if i.IsIOSpace() {
+		return fmt.Sprintf("I/O 0x%04X", i.Address())
 	}
-	return fmt.Sprintf("Memory 0x%016X", i.BaseAddress)
+	return fmt.Sprintf("Memory 0x%016X", i.Address())
 }
 
 // InterruptNumberString returns the interrupt number as a string
@@ -0,0 +1,289 @@
+package type4
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/earentir/gosmbios/jep106"
+)
+
+// On ARM64 processors ProcessorID holds either the MIDR_EL1 register in the
+// low dword, or, when the Arm64 SoC ID characteristic is set, the SMCCC
+// SMCCC_ARCH_SOC_ID version (low dword) and revision (high dword)
+// (DSP0134 7.5.3.3)
+
+// MIDR is a decoded MIDR_EL1 Main ID Register
+type MIDR struct {
+	Value        uint32
+	Implementer  uint8  // Bits 31:24
+	Variant      uint8  // Bits 23:20, the major revision (rN)
+	Architecture uint8  // Bits 19:16, 0xF for features in ID registers
+	PartNumber   uint16 // Bits 15:4
+	Revision     uint8  // Bits 3:0, the minor revision (pN)
+}
+
+// DecodeMIDR decodes a MIDR_EL1 value
+func DecodeMIDR(v uint32) MIDR {
+	return MIDR{
+		Value:        v,
+		Implementer:  uint8(v >> 24),
+		Variant:      uint8((v >> 20) & 0x0F),
+		Architecture: uint8((v >> 16) & 0x0F),
+		PartNumber:   uint16((v >> 4) & 0x0FFF),
+		Revision:     uint8(v & 0x0F),
+	}
+}
+
+// ImplementerName returns the name of the implementer
+func (m MIDR) ImplementerName() string {
+	armMu.RLock()
+	defer armMu.RUnlock()
+	if name, ok := armImplementers[m.Implementer]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown (0x%02X)", m.Implementer)
+}
+
+// PartName returns the core name, or an empty string if it is unknown
+func (m MIDR) PartName() string {
+	armMu.RLock()
+	defer armMu.RUnlock()
+	return armParts[armPartKey{m.Implementer, m.PartNumber}]
+}
+
+func (m MIDR) String() string {
+	part := m.PartName()
+	if part == "" {
+		part = fmt.Sprintf("part 0x%03X", m.PartNumber)
+	}
+	return fmt.Sprintf("%s %s r%dp%d", m.ImplementerName(), part, m.Variant, m.Revision)
+}
+
+// SoCID is a decoded SMCCC SoC ID
+type SoCID struct {
+	Version    uint32 // SMCCC_ARCH_SOC_ID(0)
+	Revision   uint32 // SMCCC_ARCH_SOC_ID(1), bits 30:0
+	JEP106Bank uint8  // Version bits 30:24, number of continuation codes
+	JEP106ID   uint8  // Version bits 23:16, identification code without parity
+	ID         uint16 // Version bits 15:0, implementation defined SoC ID
+}
+
+// DecodeSoCID decodes a ProcessorID holding an SMCCC SoC ID
+func DecodeSoCID(id uint64) SoCID {
+	version := uint32(id)
+	return SoCID{
+		Version:    version,
+		Revision:   uint32(id>>32) & 0x7FFFFFFF,
+		JEP106Bank: uint8((version >> 24) & 0x7F),
+		JEP106ID:   uint8((version >> 16) & 0x7F),
+		ID:         uint16(version),
+	}
+}
+
+// SiP returns the JEP-106 code of the silicon provider as bank<<8 | ID,
+// as Linux shows it in "jep106:BBII:SSSS" SoC IDs
+func (s SoCID) SiP() uint16 {
+	return uint16(s.JEP106Bank)<<8 | uint16(s.JEP106ID)
+}
+
+// SiPName returns the name of the silicon provider
+func (s SoCID) SiPName() string {
+	armMu.RLock()
+	defer armMu.RUnlock()
+	if name, ok := armSiPs[s.SiP()]; ok {
+		return name
+	}
+	if name := jep106.FromSiP(s.SiP()).Name(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Unknown (jep106:%04x)", s.SiP())
+}
+
+// Name returns the SoC name, or an empty string if it is unknown
+func (s SoCID) Name() string {
+	armMu.RLock()
+	defer armMu.RUnlock()
+	return armSoCs[armSoCKey{s.SiP(), s.ID}].Name
+}
+
+// Core returns the core of the SoC, or an empty string if it is unknown
+func (s SoCID) Core() string {
+	armMu.RLock()
+	defer armMu.RUnlock()
+	return armSoCs[armSoCKey{s.SiP(), s.ID}].Core
+}
+
+// LinuxID returns the SoC ID as shown by Linux in
+// /sys/devices/soc0/soc_id, e.g. "jep106:036b:0241"
+func (s SoCID) LinuxID() string {
+	return fmt.Sprintf("jep106:%04x:%04x", s.SiP(), s.ID)
+}
+
+func (s SoCID) String() string {
+	name := s.Name()
+	if name == "" {
+		name = fmt.Sprintf("SoC 0x%04X", s.ID)
+	}
+	return fmt.Sprintf("%s %s (%s), revision 0x%X", s.SiPName(), name, s.LinuxID(), s.Revision)
+}
+
+// isArm reports whether the processor family or manufacturer is Arm
+func (p *ProcessorInfo) isArm() bool {
+	switch p.ProcessorFamily {
+	case ProcessorFamilyARM, ProcessorFamilyARMv7, ProcessorFamilyARMv8, ProcessorFamilyARMv9:
+		return true
+	}
+	if p.X86Vendor() != "" {
+		return false
+	}
+	s := strings.ToLower(p.ProcessorManufacturer + " " + p.ProcessorVersion)
+	for _, name := range []string{"arm", "ampere", "neoverse", "cortex", "graviton", "hisilicon", "huawei", "kunpeng", "nvidia", "phytium", "qualcomm", "cavium", "marvell", "fujitsu"} {
+		if strings.Contains(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsArm64SoCID returns true if ProcessorID holds an SMCCC SoC ID rather
+// than MIDR_EL1
+func (p *ProcessorInfo) IsArm64SoCID() bool {
+	return p.ProcessorCharacteristics.Has(CharArm64SocID)
+}
+
+// MIDR decodes ProcessorID as MIDR_EL1. It returns false if the processor
+// is not an Arm processor, reports a SoC ID instead, or reports no ID.
+func (p *ProcessorInfo) MIDR() (MIDR, bool) {
+	if p.ProcessorID == 0 || p.IsArm64SoCID() || !p.isArm() {
+		return MIDR{}, false
+	}
+	return DecodeMIDR(uint32(p.ProcessorID)), true
+}
+
+// SoCID decodes ProcessorID as an SMCCC SoC ID. It returns false unless the
+// Arm64 SoC ID characteristic is set.
+func (p *ProcessorInfo) SoCID() (SoCID, bool) {
+	if p.ProcessorID == 0 || !p.IsArm64SoCID() {
+		return SoCID{}, false
+	}
+	return DecodeSoCID(p.ProcessorID), true
+}
+
+type armPartKey struct {
+	implementer uint8
+	part        uint16
+}
+
+type armSoCKey struct {
+	sip uint16
+	id  uint16
+}
+
+// ArmSoC names a SoC and its core
+type ArmSoC struct {
+	Name string
+	Core string
+}
+
+var (
+	armMu sync.RWMutex
+
+	// armImplementers are the MIDR_EL1 implementer codes
+	armImplementers = map[uint8]string{
+		0x41: "Arm",
+		0x42: "Broadcom",
+		0x43: "Cavium",
+		0x46: "Fujitsu",
+		0x48: "HiSilicon",
+		0x4E: "NVIDIA",
+		0x50: "Applied Micro",
+		0x51: "Qualcomm",
+		0x53: "Samsung",
+		0x56: "Marvell",
+		0x61: "Apple",
+		0x69: "Intel",
+		0x6D: "Microsoft",
+		0x70: "Phytium",
+		0xC0: "Ampere",
+	}
+
+	// armParts are the core names by implementer and part number
+	armParts = map[armPartKey]string{
+		{0x41, 0xD03}: "Cortex-A53",
+		{0x41, 0xD04}: "Cortex-A35",
+		{0x41, 0xD05}: "Cortex-A55",
+		{0x41, 0xD07}: "Cortex-A57",
+		{0x41, 0xD08}: "Cortex-A72",
+		{0x41, 0xD09}: "Cortex-A73",
+		{0x41, 0xD0A}: "Cortex-A75",
+		{0x41, 0xD0B}: "Cortex-A76",
+		{0x41, 0xD0C}: "Neoverse N1",
+		{0x41, 0xD0D}: "Cortex-A77",
+		{0x41, 0xD40}: "Neoverse V1",
+		{0x41, 0xD41}: "Cortex-A78",
+		{0x41, 0xD44}: "Cortex-X1",
+		{0x41, 0xD46}: "Cortex-A510",
+		{0x41, 0xD47}: "Cortex-A710",
+		{0x41, 0xD48}: "Cortex-X2",
+		{0x41, 0xD49}: "Neoverse N2",
+		{0x41, 0xD4A}: "Neoverse E1",
+		{0x41, 0xD4B}: "Cortex-A78C",
+		{0x41, 0xD4D}: "Cortex-A715",
+		{0x41, 0xD4E}: "Cortex-X3",
+		{0x41, 0xD4F}: "Neoverse V2",
+		{0x41, 0xD80}: "Cortex-A520",
+		{0x41, 0xD81}: "Cortex-A720",
+		{0x41, 0xD82}: "Cortex-X4",
+		{0x41, 0xD84}: "Neoverse V3",
+		{0x41, 0xD8E}: "Neoverse N3",
+		{0x43, 0x0A1}: "ThunderX",
+		{0x43, 0x0AF}: "ThunderX2",
+		{0x46, 0x001}: "A64FX",
+		{0x48, 0xD01}: "TaiShan v110",
+		{0x4E, 0x003}: "Denver 2",
+		{0x4E, 0x004}: "Carmel",
+		{0x50, 0x000}: "X-Gene",
+		{0x51, 0xC00}: "Falkor",
+		{0x51, 0x001}: "Oryon",
+		{0xC0, 0xAC3}: "AmpereOne",
+	}
+
+	// armSiPs name silicon providers by JEP-106 code, ahead of the
+	// jep106 table
+	armSiPs = map[uint16]string{}
+
+	// armSoCs are the SoC names by silicon provider and SoC ID
+	armSoCs = map[armSoCKey]ArmSoC{
+		{0x036B, 0x0241}: {Name: "Grace", Core: "Neoverse V2"},
+		{0x0A16, 0x0001}: {Name: "Altra", Core: "Neoverse N1"},
+	}
+)
+
+// RegisterArmImplementer names a MIDR_EL1 implementer code
+func RegisterArmImplementer(implementer uint8, name string) {
+	armMu.Lock()
+	defer armMu.Unlock()
+	armImplementers[implementer] = name
+}
+
+// RegisterArmPart names a core by MIDR_EL1 implementer and part number
+func RegisterArmPart(implementer uint8, part uint16, name string) {
+	armMu.Lock()
+	defer armMu.Unlock()
+	armParts[armPartKey{implementer, part}] = name
+}
+
+// RegisterArmSiP names a silicon provider by its JEP-106 code (bank<<8 | ID)
+func RegisterArmSiP(sip uint16, name string) {
+	armMu.Lock()
+	defer armMu.Unlock()
+	armSiPs[sip] = name
+}
+
+// RegisterArmSoC names a SoC by silicon provider and SoC ID
+func RegisterArmSoC(sip, id uint16, soc ArmSoC) {
+	armMu.Lock()
+	defer armMu.Unlock()
+	armSoCs[armSoCKey{sip, id}] = soc
+}
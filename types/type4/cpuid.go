@@ -0,0 +1,325 @@
+package type4
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// On x86 processors ProcessorID holds the CPUID leaf 1 results: the low
+// dword is the EAX processor signature and the high dword the EDX feature
+// flags (DSP0134 7.5.3.1)
+
+// CPUFeatures are the CPUID leaf 1 EDX feature flags
+type CPUFeatures uint32
+
+// CPUID leaf 1 EDX feature flags
+const (
+	FeatureFPU   CPUFeatures = 1 << 0  // x87 FPU on chip
+	FeatureVME   CPUFeatures = 1 << 1  // Virtual-8086 mode enhancement
+	FeatureDE    CPUFeatures = 1 << 2  // Debugging extensions
+	FeaturePSE   CPUFeatures = 1 << 3  // Page size extensions
+	FeatureTSC   CPUFeatures = 1 << 4  // Time stamp counter
+	FeatureMSR   CPUFeatures = 1 << 5  // RDMSR and WRMSR support
+	FeaturePAE   CPUFeatures = 1 << 6  // Physical address extensions
+	FeatureMCE   CPUFeatures = 1 << 7  // Machine check exception
+	FeatureCX8   CPUFeatures = 1 << 8  // CMPXCHG8B instruction
+	FeatureAPIC  CPUFeatures = 1 << 9  // APIC on chip
+	FeatureSEP   CPUFeatures = 1 << 11 // SYSENTER and SYSEXIT
+	FeatureMTRR  CPUFeatures = 1 << 12 // Memory type range registers
+	FeaturePGE   CPUFeatures = 1 << 13 // PTE global bit
+	FeatureMCA   CPUFeatures = 1 << 14 // Machine check architecture
+	FeatureCMOV  CPUFeatures = 1 << 15 // Conditional move instructions
+	FeaturePAT   CPUFeatures = 1 << 16 // Page attribute table
+	FeaturePSE36 CPUFeatures = 1 << 17 // 36-bit page size extension
+	FeaturePSN   CPUFeatures = 1 << 18 // Processor serial number
+	FeatureCLFSH CPUFeatures = 1 << 19 // CLFLUSH instruction
+	FeatureDS    CPUFeatures = 1 << 21 // Debug store
+	FeatureACPI  CPUFeatures = 1 << 22 // Thermal monitor and clock control
+	FeatureMMX   CPUFeatures = 1 << 23 // MMX technology
+	FeatureFXSR  CPUFeatures = 1 << 24 // FXSAVE and FXRSTOR
+	FeatureSSE   CPUFeatures = 1 << 25 // SSE
+	FeatureSSE2  CPUFeatures = 1 << 26 // SSE2
+	FeatureSS    CPUFeatures = 1 << 27 // Self snoop
+	FeatureHTT   CPUFeatures = 1 << 28 // Max APIC IDs field is valid (multi-threading)
+	FeatureTM    CPUFeatures = 1 << 29 // Thermal monitor
+	FeaturePBE   CPUFeatures = 1 << 31 // Pending break enable
+)
+
+// featureNames are the conventional names of the feature flags, by bit
+var featureNames = [32]string{
+	0: "FPU", 1: "VME", 2: "DE", 3: "PSE", 4: "TSC", 5: "MSR", 6: "PAE", 7: "MCE",
+	8: "CX8", 9: "APIC", 11: "SEP", 12: "MTRR", 13: "PGE", 14: "MCA", 15: "CMOV",
+	16: "PAT", 17: "PSE-36", 18: "PSN", 19: "CLFSH", 21: "DS", 22: "ACPI", 23: "MMX",
+	24: "FXSR", 25: "SSE", 26: "SSE2", 27: "SS", 28: "HTT", 29: "TM", 31: "PBE",
+}
+
+// Has checks if a feature flag is set
+func (f CPUFeatures) Has(flag CPUFeatures) bool {
+	return f&flag != 0
+}
+
+// Names returns the names of the set feature flags
+func (f CPUFeatures) Names() []string {
+	var names []string
+	for bit, name := range featureNames {
+		if name != "" && f&(1<<bit) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (f CPUFeatures) String() string {
+	if f == 0 {
+		return "None"
+	}
+	return strings.Join(f.Names(), " ")
+}
+
+// CPUID is the decoded x86 ProcessorID
+type CPUID struct {
+	Signature      uint32      // CPUID leaf 1 EAX
+	Features       CPUFeatures // CPUID leaf 1 EDX
+	Stepping       uint8
+	Model          uint8 // Base model, bits 7:4
+	Family         uint8 // Base family, bits 11:8
+	Type           uint8 // Processor type, bits 13:12 (0 = original OEM)
+	ExtendedModel  uint8 // Bits 19:16
+	ExtendedFamily uint8 // Bits 27:20
+	// DisplayFamily and DisplayModel combine the base and extended fields
+	// as the vendors document them
+	DisplayFamily uint16
+	DisplayModel  uint16
+}
+
+// DecodeCPUID decodes an x86 ProcessorID
+func DecodeCPUID(id uint64) CPUID {
+	eax := uint32(id)
+	c := CPUID{
+		Signature:      eax,
+		Features:       CPUFeatures(id >> 32),
+		Stepping:       uint8(eax & 0x0F),
+		Model:          uint8((eax >> 4) & 0x0F),
+		Family:         uint8((eax >> 8) & 0x0F),
+		Type:           uint8((eax >> 12) & 0x03),
+		ExtendedModel:  uint8((eax >> 16) & 0x0F),
+		ExtendedFamily: uint8((eax >> 20) & 0xFF),
+	}
+
+	c.DisplayFamily = uint16(c.Family)
+	if c.Family == 0x0F {
+		c.DisplayFamily += uint16(c.ExtendedFamily)
+	}
+	c.DisplayModel = uint16(c.Model)
+	if c.Family == 0x06 || c.Family == 0x0F {
+		c.DisplayModel |= uint16(c.ExtendedModel) << 4
+	}
+	return c
+}
+
+func (c CPUID) String() string {
+	return fmt.Sprintf("Family 0x%X, Model 0x%X, Stepping %d", c.DisplayFamily, c.DisplayModel, c.Stepping)
+}
+
+// x86 processor vendors
+const (
+	VendorIntel   = "Intel"
+	VendorAMD     = "AMD"
+	VendorHygon   = "Hygon"
+	VendorZhaoxin = "Zhaoxin"
+	VendorVIA     = "VIA"
+)
+
+// X86Vendor returns the x86 vendor named by the manufacturer or version
+// string, or an empty string for other architectures or unknown vendors
+func (p *ProcessorInfo) X86Vendor() string {
+	s := strings.ToLower(p.ProcessorManufacturer + " " + p.ProcessorVersion)
+	switch {
+	case strings.Contains(s, "intel"):
+		return VendorIntel
+	case strings.Contains(s, "hygon"):
+		return VendorHygon
+	case strings.Contains(s, "amd"), strings.Contains(s, "advanced micro"):
+		return VendorAMD
+	case strings.Contains(s, "zhaoxin"):
+		return VendorZhaoxin
+	case strings.Contains(s, "centaur"), strings.HasPrefix(s, "via "):
+		return VendorVIA
+	}
+	return ""
+}
+
+// CPUID decodes ProcessorID as an x86 CPUID signature. It returns false if
+// the processor is not an x86 processor of a known vendor or reports no ID.
+func (p *ProcessorInfo) CPUID() (CPUID, bool) {
+	if p.ProcessorID == 0 || p.ProcessorCharacteristics.Has(CharArm64SocID) || p.X86Vendor() == "" {
+		return CPUID{}, false
+	}
+	return DecodeCPUID(p.ProcessorID), true
+}
+
+// Microarchitecture returns the microarchitecture name of the processor,
+// or an empty string if it is unknown. For Arm processors this is the core,
+// such as "Neoverse N1".
+func (p *ProcessorInfo) Microarchitecture() string {
+	if c, ok := p.CPUID(); ok {
+		return LookupMicroarchitecture(p.X86Vendor(), c)
+	}
+	if m, ok := p.MIDR(); ok {
+		return m.PartName()
+	}
+	if s, ok := p.SoCID(); ok {
+		return s.Core()
+	}
+	return ""
+}
+
+// Microarch maps a range of CPUID signatures to a microarchitecture name
+type Microarch struct {
+	Vendor      string
+	Family      uint16
+	ModelMin    uint16
+	ModelMax    uint16
+	SteppingMin uint8
+	SteppingMax uint8 // 0 means any
+	Name        string
+}
+
+// matches reports whether the entry covers a signature
+func (m *Microarch) matches(vendor string, c CPUID) bool {
+	if m.Vendor != vendor || m.Family != c.DisplayFamily {
+		return false
+	}
+	if c.DisplayModel < m.ModelMin || c.DisplayModel > m.ModelMax {
+		return false
+	}
+	if c.Stepping < m.SteppingMin || (m.SteppingMax != 0 && c.Stepping > m.SteppingMax) {
+		return false
+	}
+	return true
+}
+
+// model returns entries for single models of a vendor and family
+func model(vendor string, family uint16, name string, models ...uint16) []Microarch {
+	entries := make([]Microarch, len(models))
+	for i, m := range models {
+		entries[i] = Microarch{Vendor: vendor, Family: family, ModelMin: m, ModelMax: m, Name: name}
+	}
+	return entries
+}
+
+// span returns an entry for a model range of a vendor and family
+func span(vendor string, family, min, max uint16, name string) []Microarch {
+	return []Microarch{{Vendor: vendor, Family: family, ModelMin: min, ModelMax: max, Name: name}}
+}
+
+// microarchs is the microarchitecture table; the first match wins, so
+// stepping-specific entries come before the model they refine
+var (
+	microarchsMu sync.RWMutex
+	microarchs   = concat(
+		// Intel steppings that share a model
+		[]Microarch{
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x55, ModelMax: 0x55, SteppingMin: 5, SteppingMax: 7, Name: "Cascade Lake"},
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x55, ModelMax: 0x55, SteppingMin: 10, SteppingMax: 11, Name: "Cooper Lake"},
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x8E, ModelMax: 0x8E, SteppingMin: 10, SteppingMax: 10, Name: "Kaby Lake R"},
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x8E, ModelMax: 0x8E, SteppingMin: 11, SteppingMax: 11, Name: "Whiskey Lake"},
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x8E, ModelMax: 0x8E, SteppingMin: 12, SteppingMax: 12, Name: "Comet Lake"},
+			{Vendor: VendorIntel, Family: 6, ModelMin: 0x9E, ModelMax: 0x9E, SteppingMin: 10, SteppingMax: 13, Name: "Coffee Lake"},
+		},
+		// Intel Core and Xeon
+		model(VendorIntel, 6, "Pentium M", 0x09, 0x0D),
+		model(VendorIntel, 6, "Core", 0x0E),
+		model(VendorIntel, 6, "Merom", 0x0F, 0x16),
+		model(VendorIntel, 6, "Penryn", 0x17, 0x1D),
+		model(VendorIntel, 6, "Nehalem", 0x1A, 0x1E, 0x1F, 0x2E),
+		model(VendorIntel, 6, "Westmere", 0x25, 0x2C, 0x2F),
+		model(VendorIntel, 6, "Sandy Bridge", 0x2A, 0x2D),
+		model(VendorIntel, 6, "Ivy Bridge", 0x3A, 0x3E),
+		model(VendorIntel, 6, "Haswell", 0x3C, 0x3F, 0x45, 0x46),
+		model(VendorIntel, 6, "Broadwell", 0x3D, 0x47, 0x4F, 0x56),
+		model(VendorIntel, 6, "Skylake", 0x4E, 0x5E, 0x55),
+		model(VendorIntel, 6, "Kaby Lake", 0x8E, 0x9E),
+		model(VendorIntel, 6, "Cannon Lake", 0x66),
+		model(VendorIntel, 6, "Ice Lake", 0x6A, 0x6C, 0x7D, 0x7E),
+		model(VendorIntel, 6, "Comet Lake", 0xA5, 0xA6),
+		model(VendorIntel, 6, "Tiger Lake", 0x8C, 0x8D),
+		model(VendorIntel, 6, "Rocket Lake", 0xA7),
+		model(VendorIntel, 6, "Alder Lake", 0x97, 0x9A, 0xBE),
+		model(VendorIntel, 6, "Raptor Lake", 0xB7, 0xBA, 0xBF),
+		model(VendorIntel, 6, "Meteor Lake", 0xAA, 0xAC),
+		model(VendorIntel, 6, "Lunar Lake", 0xBD),
+		model(VendorIntel, 6, "Arrow Lake", 0xC5, 0xC6),
+		model(VendorIntel, 6, "Panther Lake", 0xCC),
+		model(VendorIntel, 6, "Sapphire Rapids", 0x8F),
+		model(VendorIntel, 6, "Emerald Rapids", 0xCF),
+		model(VendorIntel, 6, "Granite Rapids", 0xAD, 0xAE),
+		model(VendorIntel, 6, "Sierra Forest", 0xAF),
+		model(VendorIntel, 6, "Clearwater Forest", 0xDD),
+		// Intel Atom and Xeon Phi
+		model(VendorIntel, 6, "Bonnell", 0x1C, 0x26),
+		model(VendorIntel, 6, "Saltwell", 0x27, 0x35, 0x36),
+		model(VendorIntel, 6, "Silvermont", 0x37, 0x4A, 0x4D, 0x5A, 0x5D),
+		model(VendorIntel, 6, "Airmont", 0x4C, 0x75),
+		model(VendorIntel, 6, "Goldmont", 0x5C, 0x5F),
+		model(VendorIntel, 6, "Goldmont Plus", 0x7A),
+		model(VendorIntel, 6, "Tremont", 0x86, 0x96, 0x9C),
+		model(VendorIntel, 6, "Knights Landing", 0x57),
+		model(VendorIntel, 6, "Knights Mill", 0x85),
+		span(VendorIntel, 0x0F, 0x00, 0x0F, "NetBurst"),
+		// AMD
+		span(VendorAMD, 0x0F, 0x00, 0xFF, "K8"),
+		span(VendorAMD, 0x10, 0x00, 0xFF, "K10"),
+		span(VendorAMD, 0x11, 0x00, 0xFF, "K8/K10 (Griffin)"),
+		span(VendorAMD, 0x12, 0x00, 0xFF, "K10 (Llano)"),
+		span(VendorAMD, 0x14, 0x00, 0xFF, "Bobcat"),
+		span(VendorAMD, 0x15, 0x00, 0x0F, "Bulldozer"),
+		span(VendorAMD, 0x15, 0x10, 0x1F, "Piledriver"),
+		span(VendorAMD, 0x15, 0x30, 0x3F, "Steamroller"),
+		span(VendorAMD, 0x15, 0x60, 0x7F, "Excavator"),
+		span(VendorAMD, 0x16, 0x00, 0x0F, "Jaguar"),
+		span(VendorAMD, 0x16, 0x30, 0x3F, "Puma"),
+		model(VendorAMD, 0x17, "Zen", 0x01, 0x11, 0x20),
+		model(VendorAMD, 0x17, "Zen+", 0x08, 0x18),
+		span(VendorAMD, 0x17, 0x30, 0xFF, "Zen 2"),
+		span(VendorAMD, 0x19, 0x00, 0x0F, "Zen 3"),
+		span(VendorAMD, 0x19, 0x10, 0x1F, "Zen 4"),
+		span(VendorAMD, 0x19, 0x20, 0x2F, "Zen 3"),
+		span(VendorAMD, 0x19, 0x40, 0x4F, "Zen 3+"),
+		span(VendorAMD, 0x19, 0x50, 0x5F, "Zen 3"),
+		span(VendorAMD, 0x19, 0x60, 0x7F, "Zen 4"),
+		span(VendorAMD, 0x19, 0xA0, 0xAF, "Zen 4c"),
+		span(VendorAMD, 0x1A, 0x00, 0xFF, "Zen 5"),
+		span(VendorHygon, 0x18, 0x00, 0xFF, "Dhyana"),
+	)
+)
+
+func concat(lists ...[]Microarch) []Microarch {
+	var all []Microarch
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	return all
+}
+
+// RegisterMicroarchitecture adds a table entry. Registered entries take
+// precedence over the built-in table.
+func RegisterMicroarchitecture(m Microarch) {
+	microarchsMu.Lock()
+	defer microarchsMu.Unlock()
+	microarchs = append([]Microarch{m}, microarchs...)
+}
+
+// LookupMicroarchitecture returns the microarchitecture name for a vendor
+// and CPUID signature, or an empty string if it is not in the table
+func LookupMicroarchitecture(vendor string, c CPUID) string {
+	microarchsMu.RLock()
+	defer microarchsMu.RUnlock()
+	for i := range microarchs {
+		if microarchs[i].matches(vendor, c) {
+			return microarchs[i].Name
+		}
+	}
+	return ""
+}
@@ -120,11 +120,11 @@ const (
 	ProcessorFamilyAMDRyzen5         ProcessorFamily = 0x6D
 	ProcessorFamilyAMDRyzen7         ProcessorFamily = 0x6E
 	ProcessorFamilyAMDRyzen9         ProcessorFamily = 0x6F
-	ProcessorFamilyARM               ProcessorFamily = 0x100
-	ProcessorFamilyARMv7             ProcessorFamily = 0x101
-	ProcessorFamilyARMv8             ProcessorFamily = 0x102
-	ProcessorFamilyARMv9             ProcessorFamily = 0x103
+	ProcessorFamilyARMv7             ProcessorFamily = 0x100
+	ProcessorFamilyARMv8             ProcessorFamily = 0x101
+	ProcessorFamilyARMv9             ProcessorFamily = 0x102
 	ProcessorFamilyAppleSilicon      ProcessorFamily = 0x110 // Custom, not official
+	ProcessorFamilyARM               ProcessorFamily = 0x118 // Without an architecture version
 	ProcessorFamilyIndicatorFamily2  ProcessorFamily = 0xFE  // Use ProcessorFamily2 field
 )
 
@@ -170,10 +170,10 @@ func (pf ProcessorFamily) String() string {
 		ProcessorFamilyAMDRyzen5:         "AMD Ryzen 5",
 		ProcessorFamilyAMDRyzen7:         "AMD Ryzen 7",
 		ProcessorFamilyAMDRyzen9:         "AMD Ryzen 9",
-		ProcessorFamilyARM:               "ARM",
 		ProcessorFamilyARMv7:             "ARMv7",
 		ProcessorFamilyARMv8:             "ARMv8",
 		ProcessorFamilyARMv9:             "ARMv9",
+		ProcessorFamilyARM:               "ARM",
 	}
 
 	if name, ok := families[pf]; ok {
@@ -519,6 +519,17 @@ func GetAll(sm *gosmbios.SMBIOS) ([]*ProcessorInfo, error) {
 	return processors, nil
 }
 
+// GetByHandle retrieves a Processor Information structure by its handle
+func GetByHandle(sm *gosmbios.SMBIOS, handle uint16) (*ProcessorInfo, error) {
+	structures := sm.GetStructures(StructureType)
+	for i := range structures {
+		if structures[i].Header.Handle == handle {
+			return Parse(&structures[i])
+		}
+	}
+	return nil, gosmbios.ErrNotFound
+}
+
 // GetCoreCount returns the actual core count (handling the extension field)
 func (p *ProcessorInfo) GetCoreCount() uint16 {
 	if p.CoreCount == 0xFF && p.CoreCount2 != 0 {
@@ -550,3 +561,16 @@ func (p *ProcessorInfo) DisplayName() string {
 	}
 	return p.ProcessorFamily.String()
 }
+
+// Sanitized returns a copy with placeholder identity fields (such as
+// "To Be Filled By O.E.M.") replaced by empty strings.
+// SocketDesignation is kept as-is since it identifies the socket.
+func (p *ProcessorInfo) Sanitized() *ProcessorInfo {
+	c := *p
+	c.ProcessorManufacturer = gosmbios.Sanitize(p.ProcessorManufacturer)
+	c.ProcessorVersion = gosmbios.Sanitize(p.ProcessorVersion)
+	c.SerialNumber = gosmbios.Sanitize(p.SerialNumber)
+	c.AssetTag = gosmbios.Sanitize(p.AssetTag)
+	c.PartNumber = gosmbios.Sanitize(p.PartNumber)
+	return &c
+}
@@ -0,0 +1,336 @@
+package type42
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/earentir/gosmbios"
+)
+
+// Errors returned when generating the host configuration
+var (
+	ErrNoRedfishService     = errors.New("smbios: no Redfish over IP protocol record")
+	ErrNoInterfaceMatch     = errors.New("smbios: cannot identify the host interface, set the interface name")
+	ErrUnsupportedIPAddress = errors.New("smbios: host IP assignment does not give a usable address")
+	ErrInvalidHostname      = errors.New("smbios: service hostname contains whitespace or control characters")
+)
+
+// DefaultServiceHostname is the name given to the Redfish service in
+// /etc/hosts when the record has no hostname, as redfish-finder does
+const DefaultServiceHostname = "redfish-localhost"
+
+// HostConfig generates the host-side network configuration that reaches a
+// Redfish service over its host interface
+type HostConfig struct {
+	Device  *NetworkDevice
+	Redfish *RedfishOverIP
+
+	// Interface is the host network interface name, e.g. "usb0". When
+	// empty the interface is matched by MAC address, PCI path or, for
+	// systemd-networkd only, USB IDs.
+	Interface string
+
+	// Name is the connection and file name (default "redfish")
+	Name string
+}
+
+// HostConfig returns the host configuration for the first Redfish over IP
+// protocol record of a Network Host Interface
+func (m *ManagementControllerHostInterface) HostConfig() (*HostConfig, error) {
+	records := m.RedfishRecords()
+	if len(records) == 0 {
+		return nil, ErrNoRedfishService
+	}
+	return &HostConfig{Device: m.NetworkDevice, Redfish: records[0]}, nil
+}
+
+// FindHostConfig returns the host configuration for the first Network Host
+// Interface with a Redfish over IP protocol record
+func FindHostConfig(sm *gosmbios.SMBIOS) (*HostConfig, error) {
+	interfaces, err := GetAll(sm)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range interfaces {
+		if m.InterfaceType != InterfaceTypeNetworkHostIF {
+			continue
+		}
+		if hc, err := m.HostConfig(); err == nil {
+			return hc, nil
+		}
+	}
+	return nil, ErrNoRedfishService
+}
+
+func (h *HostConfig) name() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return "redfish"
+}
+
+// ipv6 reports whether the host side uses IPv6
+func (h *HostConfig) ipv6() bool {
+	r := h.Redfish
+	if r.HostIPFormat != IPAddressFormatUnknown {
+		return r.HostIPFormat == IPAddressFormatIPv6
+	}
+	return r.ServiceIPFormat == IPAddressFormatIPv6
+}
+
+// hostAddress returns the static host address in CIDR notation, or an
+// empty string if the host address is not static
+func (h *HostConfig) hostAddress() (string, error) {
+	r := h.Redfish
+	switch r.HostIPAssignment {
+	case IPAssignmentDHCP, IPAssignmentAutoConfigure:
+		return "", nil
+	case IPAssignmentStatic, IPAssignmentHostSelected:
+		prefix := r.HostPrefixLength()
+		if r.HostIP == nil || prefix < 0 {
+			return "", ErrUnsupportedIPAddress
+		}
+		return fmt.Sprintf("%s/%d", r.HostIP, prefix), nil
+	}
+	return "", ErrUnsupportedIPAddress
+}
+
+// header returns the comment lines describing the interface and service
+func (h *HostConfig) header() string {
+	var b strings.Builder
+	if h.Device != nil {
+		fmt.Fprintf(&b, "# Redfish Host Interface: %s\n", h.Device)
+	}
+	if url := h.Redfish.ServiceURL(); url != "" {
+		fmt.Fprintf(&b, "# Redfish service: %s\n", url)
+	}
+	if !h.Redfish.ServiceUUID.IsZero() {
+		fmt.Fprintf(&b, "# Service UUID: %s\n", h.Redfish.ServiceUUID)
+	}
+	if h.Redfish.ServiceVLAN != 0 {
+		fmt.Fprintf(&b, "# Service VLAN: %d\n", h.Redfish.ServiceVLAN)
+	}
+	return b.String()
+}
+
+// vlan returns the VLAN ID of the service, or 0 if it is not on a VLAN
+func (h *HostConfig) vlan() uint32 {
+	return h.Redfish.ServiceVLAN
+}
+
+// VLANInterface returns the name of the VLAN interface created on the host
+// interface when the service is on a VLAN, e.g. "redfish.4000", or an
+// empty string otherwise
+func (h *HostConfig) VLANInterface() string {
+	if h.vlan() == 0 {
+		return ""
+	}
+	name := fmt.Sprintf("%s.%d", h.name(), h.vlan())
+	if len(name) > 15 {
+		// Longer than the kernel allows for an interface name
+		name = fmt.Sprintf("vlan%d", h.vlan())
+	}
+	return name
+}
+
+// NetworkdFileName returns the file name for the systemd-networkd unit
+func (h *HostConfig) NetworkdFileName() string {
+	return "50-" + h.name() + ".network"
+}
+
+// Networkd returns a systemd-networkd .network unit for the interface. When
+// the service is on a VLAN, the unit only attaches the VLAN to the link, and
+// NetworkdNetDev and NetworkdVLAN return the units that create and address
+// the VLAN interface.
+func (h *HostConfig) Networkd() (string, error) {
+	address, err := h.hostAddress()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(h.header())
+	b.WriteString("[Match]\n")
+	switch d := h.Device; {
+	case h.Interface != "":
+		fmt.Fprintf(&b, "Name=%s\n", h.Interface)
+	case d != nil && len(d.MACAddress) > 0:
+		fmt.Fprintf(&b, "MACAddress=%s\n", d.MACAddress)
+	case d != nil && d.PCIAddress() != "":
+		fmt.Fprintf(&b, "Path=pci-%s\n", d.PCIAddress())
+	case d != nil && d.Type.IsUSB():
+		fmt.Fprintf(&b, "Property=ID_VENDOR_ID=%04x ID_MODEL_ID=%04x\n", d.USBVendorID, d.USBProductID)
+	default:
+		return "", ErrNoInterfaceMatch
+	}
+
+	b.WriteString("\n[Link]\nRequiredForOnline=no\n\n[Network]\n")
+	if vlan := h.VLANInterface(); vlan != "" {
+		fmt.Fprintf(&b, "VLAN=%s\nLinkLocalAddressing=no\n", vlan)
+		return b.String(), nil
+	}
+	h.networkdAddress(&b, address)
+	return b.String(), nil
+}
+
+// networkdAddress writes the [Network] addressing of the interface that
+// reaches the service
+func (h *HostConfig) networkdAddress(b *strings.Builder, address string) {
+	switch {
+	case address != "":
+		fmt.Fprintf(b, "Address=%s\n", address)
+		b.WriteString("LinkLocalAddressing=no\n")
+	case h.Redfish.HostIPAssignment == IPAssignmentDHCP && h.ipv6():
+		b.WriteString("DHCP=ipv6\n\n[DHCPv6]\nUseDNS=no\n")
+	case h.Redfish.HostIPAssignment == IPAssignmentDHCP:
+		b.WriteString("DHCP=ipv4\n\n[DHCPv4]\nUseDNS=no\nUseRoutes=no\n")
+	case h.ipv6():
+		b.WriteString("LinkLocalAddressing=ipv6\nIPv6AcceptRA=yes\n")
+	default:
+		b.WriteString("LinkLocalAddressing=ipv4\n")
+	}
+}
+
+// NetworkdNetDevFileName returns the file name for the systemd-networkd
+// VLAN .netdev unit
+func (h *HostConfig) NetworkdNetDevFileName() string {
+	return "50-" + h.name() + "-vlan.netdev"
+}
+
+// NetworkdNetDev returns a systemd-networkd .netdev unit creating the VLAN
+// interface, or an empty string if the service is not on a VLAN
+func (h *HostConfig) NetworkdNetDev() string {
+	vlan := h.VLANInterface()
+	if vlan == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s[NetDev]\nName=%s\nKind=vlan\n\n[VLAN]\nId=%d\n", h.header(), vlan, h.vlan())
+}
+
+// NetworkdVLANFileName returns the file name for the systemd-networkd unit
+// of the VLAN interface
+func (h *HostConfig) NetworkdVLANFileName() string {
+	return "50-" + h.name() + "-vlan.network"
+}
+
+// NetworkdVLAN returns a systemd-networkd .network unit addressing the VLAN
+// interface, or an empty string if the service is not on a VLAN
+func (h *HostConfig) NetworkdVLAN() (string, error) {
+	vlan := h.VLANInterface()
+	if vlan == "" {
+		return "", nil
+	}
+	address, err := h.hostAddress()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(h.header())
+	fmt.Fprintf(&b, "[Match]\nName=%s\n", vlan)
+	b.WriteString("\n[Link]\nRequiredForOnline=no\n\n[Network]\n")
+	h.networkdAddress(&b, address)
+	return b.String(), nil
+}
+
+// NetworkManagerFileName returns the file name for the NetworkManager keyfile
+func (h *HostConfig) NetworkManagerFileName() string {
+	return h.name() + ".nmconnection"
+}
+
+// NetworkManager returns a NetworkManager keyfile for the interface, or
+// for a VLAN connection on it when the service is on a VLAN. The file must
+// be owned by root with mode 0600 to be loaded.
+func (h *HostConfig) NetworkManager() (string, error) {
+	address, err := h.hostAddress()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(h.header())
+	d := h.Device
+	if vlan := h.VLANInterface(); vlan != "" {
+		// The parent is named by interface, or matched by MAC address;
+		// NetworkManager cannot match a VLAN parent by PCI path
+		fmt.Fprintf(&b, "[connection]\nid=%s\ntype=vlan\ninterface-name=%s\n", h.name(), vlan)
+		switch {
+		case h.Interface != "":
+			fmt.Fprintf(&b, "\n[vlan]\nid=%d\nparent=%s\n", h.vlan(), h.Interface)
+		case d != nil && len(d.MACAddress) > 0:
+			fmt.Fprintf(&b, "\n[ethernet]\nmac-address=%s\n", strings.ToUpper(d.MACAddress.String()))
+			fmt.Fprintf(&b, "\n[vlan]\nid=%d\n", h.vlan())
+		default:
+			return "", ErrNoInterfaceMatch
+		}
+	} else {
+		fmt.Fprintf(&b, "[connection]\nid=%s\ntype=ethernet\n", h.name())
+		switch {
+		case h.Interface != "":
+			fmt.Fprintf(&b, "interface-name=%s\n", h.Interface)
+		case d != nil && len(d.MACAddress) > 0:
+			fmt.Fprintf(&b, "\n[ethernet]\nmac-address=%s\n", strings.ToUpper(d.MACAddress.String()))
+		case d != nil && d.PCIAddress() != "":
+			fmt.Fprintf(&b, "\n[match]\npath=pci-%s\n", d.PCIAddress())
+		default:
+			return "", ErrNoInterfaceMatch
+		}
+	}
+
+	method := "link-local"
+	switch {
+	case address != "":
+		method = "manual"
+	case h.Redfish.HostIPAssignment == IPAssignmentDHCP:
+		method = "auto"
+	}
+	ip, other := "ipv4", "ipv6"
+	if h.ipv6() {
+		ip, other = "ipv6", "ipv4"
+	}
+	fmt.Fprintf(&b, "\n[%s]\nmethod=%s\n", ip, method)
+	if address != "" {
+		fmt.Fprintf(&b, "address1=%s\n", address)
+	}
+	if method == "auto" {
+		b.WriteString("ignore-auto-dns=true\n")
+	}
+	b.WriteString("never-default=true\n")
+	fmt.Fprintf(&b, "\n[%s]\nmethod=disabled\n", other)
+	return b.String(), nil
+}
+
+// HostsEntry returns the /etc/hosts line mapping the service hostname to
+// its address, or an empty string if the address is not known in advance.
+// It returns ErrInvalidHostname for firmware hostnames that would corrupt
+// the file, such as ones with whitespace, control characters or "#".
+func (h *HostConfig) HostsEntry() (string, error) {
+	r := h.Redfish
+	if r.ServiceIP == nil {
+		return "", nil
+	}
+	name := r.ServiceHostname
+	if name == "" {
+		name = DefaultServiceHostname
+	}
+	if !validHostname(name) {
+		return "", ErrInvalidHostname
+	}
+	return fmt.Sprintf("%s\t%s\n", r.ServiceIP, name), nil
+}
+
+// validHostname reports whether a hostname can be written to /etc/hosts
+// and used in a URL
+func validHostname(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, c := range name {
+		if unicode.IsSpace(c) || unicode.IsControl(c) || c == '#' || c == '/' {
+			return false
+		}
+	}
+	return true
+}
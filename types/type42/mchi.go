@@ -16,6 +16,7 @@ type ManagementControllerHostInterface struct {
 	Header                      gosmbios.Header
 	InterfaceType               InterfaceType
 	InterfaceTypeSpecificData   []byte
+	NetworkDevice               *NetworkDevice // Decoded for Network Host Interfaces
 	ProtocolRecords             []ProtocolRecord
 }
 
@@ -114,6 +115,9 @@ func Parse(s *gosmbios.Structure) (*ManagementControllerHostInterface, error) {
 		if ifDataLen > 0 && len(s.Data) >= 6+int(ifDataLen) {
 			info.InterfaceTypeSpecificData = make([]byte, ifDataLen)
 			copy(info.InterfaceTypeSpecificData, s.Data[0x06:0x06+ifDataLen])
+			if info.InterfaceType == InterfaceTypeNetworkHostIF {
+				info.NetworkDevice = parseNetworkDevice(info.InterfaceTypeSpecificData, s.GetString)
+			}
 		}
 
 		// Parse protocol records
@@ -0,0 +1,403 @@
+package type42
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type1"
+)
+
+// Network Host Interface device descriptors and the Redfish over IP
+// protocol record are defined by DSP0270 Redfish Host Interface
+// Specification 1.3
+
+// DeviceType identifies the Network Host Interface device descriptor
+type DeviceType uint8
+
+const (
+	DeviceTypeUSB   DeviceType = 0x02 // USB Network Interface
+	DeviceTypePCI   DeviceType = 0x03 // PCI/PCIe Network Interface
+	DeviceTypeUSBv2 DeviceType = 0x04 // USB Network Interface v2
+	DeviceTypePCIv2 DeviceType = 0x05 // PCI/PCIe Network Interface v2
+)
+
+func (d DeviceType) String() string {
+	switch d {
+	case DeviceTypeUSB:
+		return "USB Network Interface"
+	case DeviceTypePCI:
+		return "PCI/PCIe Network Interface"
+	case DeviceTypeUSBv2:
+		return "USB Network Interface v2"
+	case DeviceTypePCIv2:
+		return "PCI/PCIe Network Interface v2"
+	default:
+		if d >= 0x80 {
+			return fmt.Sprintf("OEM Defined (0x%02X)", uint8(d))
+		}
+		return fmt.Sprintf("Unknown (0x%02X)", uint8(d))
+	}
+}
+
+// IsUSB returns true for USB device descriptors
+func (d DeviceType) IsUSB() bool {
+	return d == DeviceTypeUSB || d == DeviceTypeUSBv2
+}
+
+// IsPCI returns true for PCI/PCIe device descriptors
+func (d DeviceType) IsPCI() bool {
+	return d == DeviceTypePCI || d == DeviceTypePCIv2
+}
+
+// DeviceCharacteristics are the v2 device descriptor characteristics
+type DeviceCharacteristics uint16
+
+// CharCredentialBootstrapping is set when the device supports the Redfish
+// credential bootstrapping via IPMI commands
+const CharCredentialBootstrapping DeviceCharacteristics = 1 << 0
+
+// Has checks if a characteristic is set
+func (c DeviceCharacteristics) Has(flag DeviceCharacteristics) bool {
+	return c&flag != 0
+}
+
+// NetworkDevice is the decoded device descriptor of a Network Host
+// Interface. Fields not present in a descriptor type are zero.
+type NetworkDevice struct {
+	Type DeviceType
+
+	// USB descriptors
+	USBVendorID  uint16
+	USBProductID uint16
+	SerialNumber string
+
+	// PCI descriptors
+	PCIVendorID          uint16
+	PCIDeviceID          uint16
+	PCISubsystemVendorID uint16
+	PCISubsystemID       uint16
+	Segment              uint16 // v2 only
+	Bus                  uint8  // v2 only
+	Device               uint8  // v2 only
+	Function             uint8  // v2 only
+
+	// v2 descriptors
+	MACAddress                    net.HardwareAddr
+	Characteristics               DeviceCharacteristics
+	CredentialBootstrappingHandle uint16
+}
+
+// PCIAddress returns the v2 PCI address as "ssss:bb:dd.f", or an empty
+// string if the descriptor has none
+func (d *NetworkDevice) PCIAddress() string {
+	if d.Type != DeviceTypePCIv2 {
+		return ""
+	}
+	return fmt.Sprintf("%04x:%02x:%02x.%x", d.Segment, d.Bus, d.Device, d.Function)
+}
+
+func (d *NetworkDevice) String() string {
+	var s string
+	switch {
+	case d.Type.IsUSB():
+		s = fmt.Sprintf("USB %04x:%04x", d.USBVendorID, d.USBProductID)
+		if d.SerialNumber != "" {
+			s += " serial " + d.SerialNumber
+		}
+	case d.Type.IsPCI():
+		s = fmt.Sprintf("PCI %04x:%04x (subsystem %04x:%04x)", d.PCIVendorID, d.PCIDeviceID, d.PCISubsystemVendorID, d.PCISubsystemID)
+		if addr := d.PCIAddress(); addr != "" {
+			s += " at " + addr
+		}
+	default:
+		return d.Type.String()
+	}
+	if len(d.MACAddress) > 0 {
+		s += " MAC " + d.MACAddress.String()
+	}
+	return s
+}
+
+// parseNetworkDevice decodes the interface type specific data of a Network
+// Host Interface; getString resolves v2 serial number string numbers
+func parseNetworkDevice(data []byte, getString func(uint8) string) *NetworkDevice {
+	if len(data) < 1 {
+		return nil
+	}
+	d := &NetworkDevice{Type: DeviceType(data[0])}
+	b := data[1:]
+
+	switch d.Type {
+	case DeviceTypeUSB:
+		// Vendor ID, Product ID, then a USB string descriptor
+		if len(b) < 4 {
+			return d
+		}
+		d.USBVendorID = binary.LittleEndian.Uint16(b[0:])
+		d.USBProductID = binary.LittleEndian.Uint16(b[2:])
+		d.SerialNumber = usbString(b[4:])
+	case DeviceTypePCI:
+		if len(b) < 8 {
+			return d
+		}
+		d.PCIVendorID = binary.LittleEndian.Uint16(b[0:])
+		d.PCIDeviceID = binary.LittleEndian.Uint16(b[2:])
+		d.PCISubsystemVendorID = binary.LittleEndian.Uint16(b[4:])
+		d.PCISubsystemID = binary.LittleEndian.Uint16(b[6:])
+	case DeviceTypeUSBv2:
+		// Length, Vendor ID, Product ID, serial number string, MAC,
+		// characteristics, credential bootstrapping handle
+		if len(b) < 6 {
+			return d
+		}
+		d.USBVendorID = binary.LittleEndian.Uint16(b[1:])
+		d.USBProductID = binary.LittleEndian.Uint16(b[3:])
+		d.SerialNumber = getString(b[5])
+		if len(b) >= 12 {
+			d.MACAddress = macAddress(b[6:12])
+		}
+		if len(b) >= 16 {
+			d.Characteristics = DeviceCharacteristics(binary.LittleEndian.Uint16(b[12:]))
+			d.CredentialBootstrappingHandle = binary.LittleEndian.Uint16(b[14:])
+		}
+	case DeviceTypePCIv2:
+		// Length, IDs, MAC, segment, bus/device/function, characteristics,
+		// credential bootstrapping handle
+		if len(b) < 9 {
+			return d
+		}
+		d.PCIVendorID = binary.LittleEndian.Uint16(b[1:])
+		d.PCIDeviceID = binary.LittleEndian.Uint16(b[3:])
+		d.PCISubsystemVendorID = binary.LittleEndian.Uint16(b[5:])
+		d.PCISubsystemID = binary.LittleEndian.Uint16(b[7:])
+		if len(b) >= 15 {
+			d.MACAddress = macAddress(b[9:15])
+		}
+		if len(b) >= 19 {
+			d.Segment = binary.LittleEndian.Uint16(b[15:])
+			bdf := binary.LittleEndian.Uint16(b[17:])
+			d.Bus = uint8(bdf >> 8)
+			d.Device = uint8(bdf>>3) & 0x1F
+			d.Function = uint8(bdf) & 0x07
+		}
+		if len(b) >= 23 {
+			d.Characteristics = DeviceCharacteristics(binary.LittleEndian.Uint16(b[19:]))
+			d.CredentialBootstrappingHandle = binary.LittleEndian.Uint16(b[21:])
+		}
+	}
+	return d
+}
+
+// usbString decodes a USB string descriptor (bLength, bDescriptorType 0x03,
+// UTF-16LE text)
+func usbString(b []byte) string {
+	if len(b) < 2 || b[1] != 0x03 {
+		return ""
+	}
+	n := int(b[0])
+	if n > len(b) {
+		n = len(b)
+	}
+	var units []uint16
+	for i := 2; i+1 < n; i += 2 {
+		units = append(units, binary.LittleEndian.Uint16(b[i:]))
+	}
+	return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+}
+
+// macAddress returns a copy of a MAC address, or nil if it is all zeros
+func macAddress(b []byte) net.HardwareAddr {
+	for _, v := range b {
+		if v != 0 {
+			mac := make(net.HardwareAddr, len(b))
+			copy(mac, b)
+			return mac
+		}
+	}
+	return nil
+}
+
+// IPAssignmentType is how the host or the Redfish service obtains its address
+type IPAssignmentType uint8
+
+const (
+	IPAssignmentUnknown       IPAssignmentType = 0x00
+	IPAssignmentStatic        IPAssignmentType = 0x01
+	IPAssignmentDHCP          IPAssignmentType = 0x02
+	IPAssignmentAutoConfigure IPAssignmentType = 0x03
+	IPAssignmentHostSelected  IPAssignmentType = 0x04
+)
+
+func (a IPAssignmentType) String() string {
+	switch a {
+	case IPAssignmentUnknown:
+		return "Unknown"
+	case IPAssignmentStatic:
+		return "Static"
+	case IPAssignmentDHCP:
+		return "DHCP"
+	case IPAssignmentAutoConfigure:
+		return "AutoConfigure"
+	case IPAssignmentHostSelected:
+		return "Host Selected"
+	default:
+		return fmt.Sprintf("Unknown (0x%02X)", uint8(a))
+	}
+}
+
+// IPAddressFormat is the format of the address and mask fields
+type IPAddressFormat uint8
+
+const (
+	IPAddressFormatUnknown IPAddressFormat = 0x00
+	IPAddressFormatIPv4    IPAddressFormat = 0x01
+	IPAddressFormatIPv6    IPAddressFormat = 0x02
+)
+
+func (f IPAddressFormat) String() string {
+	switch f {
+	case IPAddressFormatUnknown:
+		return "Unknown"
+	case IPAddressFormatIPv4:
+		return "IPv4"
+	case IPAddressFormatIPv6:
+		return "IPv6"
+	default:
+		return fmt.Sprintf("Unknown (0x%02X)", uint8(f))
+	}
+}
+
+// RedfishOverIP is the decoded Redfish over IP protocol record
+type RedfishOverIP struct {
+	ServiceUUID type1.UUID
+
+	HostIPAssignment IPAssignmentType
+	HostIPFormat     IPAddressFormat
+	HostIP           net.IP
+	HostMask         net.IP
+
+	ServiceIPDiscovery IPAssignmentType
+	ServiceIPFormat    IPAddressFormat
+	ServiceIP          net.IP
+	ServiceMask        net.IP
+	ServicePort        uint16
+	ServiceVLAN        uint32
+	ServiceHostname    string
+}
+
+// redfishOverIPLength is the length of the record up to and including the
+// hostname length
+const redfishOverIPLength = 91
+
+// RedfishOverIP decodes a Redfish over IP protocol record
+func (p *ProtocolRecord) RedfishOverIP() (*RedfishOverIP, error) {
+	d := p.ProtocolTypeSpecific
+	if p.ProtocolType != ProtocolTypeRedfishOverIP || len(d) < redfishOverIPLength {
+		return nil, gosmbios.ErrInvalidStructure
+	}
+
+	r := &RedfishOverIP{
+		HostIPAssignment:   IPAssignmentType(d[16]),
+		HostIPFormat:       IPAddressFormat(d[17]),
+		ServiceIPDiscovery: IPAssignmentType(d[50]),
+		ServiceIPFormat:    IPAddressFormat(d[51]),
+		ServicePort:        binary.LittleEndian.Uint16(d[84:]),
+		ServiceVLAN:        binary.LittleEndian.Uint32(d[86:]),
+	}
+	copy(r.ServiceUUID[:], d[0:16])
+	r.HostIP = ipAddress(r.HostIPFormat, d[18:34])
+	r.HostMask = ipAddress(r.HostIPFormat, d[34:50])
+	r.ServiceIP = ipAddress(r.ServiceIPFormat, d[52:68])
+	r.ServiceMask = ipAddress(r.ServiceIPFormat, d[68:84])
+
+	n := int(d[90])
+	if redfishOverIPLength+n > len(d) {
+		n = len(d) - redfishOverIPLength
+	}
+	r.ServiceHostname = strings.TrimRight(string(d[redfishOverIPLength:redfishOverIPLength+n]), "\x00")
+	return r, nil
+}
+
+// ipAddress decodes a 16-byte address field; IPv4 addresses are in the
+// first four bytes. It returns nil for unknown formats and zero addresses.
+func ipAddress(format IPAddressFormat, b []byte) net.IP {
+	var ip net.IP
+	switch format {
+	case IPAddressFormatIPv4:
+		ip = net.IPv4(b[0], b[1], b[2], b[3]).To4()
+	case IPAddressFormatIPv6:
+		ip = make(net.IP, net.IPv6len)
+		copy(ip, b)
+	default:
+		return nil
+	}
+	if ip.IsUnspecified() {
+		return nil
+	}
+	return ip
+}
+
+// prefixLength returns the prefix length of a mask, or -1 if it is not a
+// contiguous mask
+func prefixLength(mask net.IP) int {
+	if mask == nil {
+		return -1
+	}
+	ones, bits := net.IPMask(mask).Size()
+	if bits == 0 {
+		return -1
+	}
+	return ones
+}
+
+// HostPrefixLength returns the prefix length of the host mask, or -1
+func (r *RedfishOverIP) HostPrefixLength() int {
+	return prefixLength(r.HostMask)
+}
+
+// ServicePrefixLength returns the prefix length of the service mask, or -1
+func (r *RedfishOverIP) ServicePrefixLength() int {
+	return prefixLength(r.ServiceMask)
+}
+
+// ServiceURL returns the Redfish service root URL, using the hostname when
+// present and valid, or an empty string if neither the hostname nor address
+// is known
+func (r *RedfishOverIP) ServiceURL() string {
+	host := r.ServiceHostname
+	if !validHostname(host) {
+		host = ""
+	}
+	if host == "" && r.ServiceIP != nil {
+		host = r.ServiceIP.String()
+		if r.ServiceIP.To4() == nil {
+			host = "[" + host + "]"
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	scheme := "https"
+	if r.ServicePort == 80 {
+		scheme = "http"
+	}
+	if r.ServicePort != 0 && r.ServicePort != 443 && r.ServicePort != 80 {
+		host = fmt.Sprintf("%s:%d", host, r.ServicePort)
+	}
+	return scheme + "://" + host + "/redfish/v1/"
+}
+
+// RedfishRecords returns the decoded Redfish over IP protocol records
+func (m *ManagementControllerHostInterface) RedfishRecords() []*RedfishOverIP {
+	var records []*RedfishOverIP
+	for i := range m.ProtocolRecords {
+		if r, err := m.ProtocolRecords[i].RedfishOverIP(); err == nil {
+			records = append(records, r)
+		}
+	}
+	return records
+}
@@ -18,7 +18,7 @@ type ProcessorAdditionalInfo struct {
 
 // ProcessorSpecificBlock contains processor-specific information
 type ProcessorSpecificBlock struct {
-	Length             uint8
+	Length             uint8 // Length of Data, after the length and type bytes
 	ProcessorType      ProcessorType
 	Data               []byte
 }
@@ -82,20 +82,21 @@ func Parse(s *gosmbios.Structure) (*ProcessorAdditionalInfo, error) {
 		ReferencedHandle: s.GetWord(0x04),
 	}
 
-	// Parse processor specific block
+	// Parse processor specific block. Block Length counts the data after
+	// the length and processor type bytes.
 	offset := 0x06
-	if offset < len(s.Data) {
+	if offset+2 <= len(s.Data) {
 		blockLen := s.GetByte(offset)
-		if blockLen >= 2 && offset+int(blockLen) <= len(s.Data) {
+		if offset+2+int(blockLen) <= len(s.Data) {
 			info.ProcessorSpecificBlock = ProcessorSpecificBlock{
 				Length:        blockLen,
 				ProcessorType: ProcessorType(s.GetByte(offset + 1)),
 			}
 
-			// Copy the remaining data
-			if blockLen > 2 {
-				info.ProcessorSpecificBlock.Data = make([]byte, blockLen-2)
-				copy(info.ProcessorSpecificBlock.Data, s.Data[offset+2:offset+int(blockLen)])
+			// Copy the processor-specific data
+			if blockLen > 0 {
+				info.ProcessorSpecificBlock.Data = make([]byte, blockLen)
+				copy(info.ProcessorSpecificBlock.Data, s.Data[offset+2:offset+2+int(blockLen)])
 			}
 		}
 	}
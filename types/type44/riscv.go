@@ -0,0 +1,283 @@
+package type44
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/earentir/gosmbios"
+	"github.com/earentir/gosmbios/types/type4"
+)
+
+// Value128 is a 128-bit little-endian value, such as a RISC-V hart ID or
+// machine ID register
+type Value128 [16]byte
+
+// Uint64 returns the low 64 bits, and whether the high 64 bits are zero
+func (v Value128) Uint64() (uint64, bool) {
+	return binary.LittleEndian.Uint64(v[:8]), binary.LittleEndian.Uint64(v[8:]) == 0
+}
+
+// IsZero returns true if the value is zero
+func (v Value128) IsZero() bool {
+	return v == Value128{}
+}
+
+func (v Value128) String() string {
+	if low, ok := v.Uint64(); ok {
+		return fmt.Sprintf("0x%X", low)
+	}
+	return fmt.Sprintf("0x%X%016X", binary.LittleEndian.Uint64(v[8:]), binary.LittleEndian.Uint64(v[:8]))
+}
+
+// RISCVExtensions is the instruction set bitmap, one bit per extension
+// letter as in the misa register (bit 0 = A ... bit 25 = Z)
+type RISCVExtensions uint32
+
+// Has checks if the extension with the given letter is supported
+func (e RISCVExtensions) Has(letter byte) bool {
+	if letter >= 'a' && letter <= 'z' {
+		letter -= 'a' - 'A'
+	}
+	if letter < 'A' || letter > 'Z' {
+		return false
+	}
+	return e&(1<<(letter-'A')) != 0
+}
+
+// Letters returns the supported extension letters in alphabetical order
+func (e RISCVExtensions) Letters() string {
+	var b strings.Builder
+	for i := 0; i < 26; i++ {
+		if e&(1<<i) != 0 {
+			b.WriteByte(byte('A' + i))
+		}
+	}
+	return b.String()
+}
+
+func (e RISCVExtensions) String() string {
+	if e == 0 {
+		return "None"
+	}
+	return e.Letters()
+}
+
+// RISCVPrivilege is the set of supported privilege levels
+type RISCVPrivilege uint8
+
+// Privilege level bits
+const (
+	RISCVPrivilegeMachine    RISCVPrivilege = 1 << 0
+	RISCVPrivilegeSupervisor RISCVPrivilege = 1 << 2
+	RISCVPrivilegeUser       RISCVPrivilege = 1 << 3
+	RISCVPrivilegeDebug      RISCVPrivilege = 1 << 7
+)
+
+// Has checks if a privilege level is supported
+func (p RISCVPrivilege) Has(flag RISCVPrivilege) bool {
+	return p&flag != 0
+}
+
+func (p RISCVPrivilege) String() string {
+	var levels []string
+	if p.Has(RISCVPrivilegeMachine) {
+		levels = append(levels, "Machine")
+	}
+	if p.Has(RISCVPrivilegeSupervisor) {
+		levels = append(levels, "Supervisor")
+	}
+	if p.Has(RISCVPrivilegeUser) {
+		levels = append(levels, "User")
+	}
+	if p.Has(RISCVPrivilegeDebug) {
+		levels = append(levels, "Debug")
+	}
+	if len(levels) == 0 {
+		return "None"
+	}
+	return strings.Join(levels, ", ")
+}
+
+// RISCVXLEN is a native base integer ISA width
+type RISCVXLEN uint8
+
+// XLEN values
+const (
+	RISCVXLENUnsupported RISCVXLEN = 0x00
+	RISCVXLEN32          RISCVXLEN = 0x01
+	RISCVXLEN64          RISCVXLEN = 0x02
+	RISCVXLEN128         RISCVXLEN = 0x03
+)
+
+// Bits returns the width in bits, or 0 if it is not supported or unknown
+func (x RISCVXLEN) Bits() int {
+	switch x {
+	case RISCVXLEN32:
+		return 32
+	case RISCVXLEN64:
+		return 64
+	case RISCVXLEN128:
+		return 128
+	}
+	return 0
+}
+
+func (x RISCVXLEN) String() string {
+	if bits := x.Bits(); bits != 0 {
+		return fmt.Sprintf("%d-bit", bits)
+	}
+	if x == RISCVXLENUnsupported {
+		return "Not Supported"
+	}
+	return fmt.Sprintf("Unknown (0x%02X)", uint8(x))
+}
+
+// RISCVBlock is the RISC-V processor-specific block
+type RISCVBlock struct {
+	Revision                   uint16 // Major in bits 15:8, minor in bits 7:0
+	Length                     uint8
+	HartID                     Value128
+	BootHart                   bool
+	MachineVendorID            Value128 // mvendorid
+	MachineArchID              Value128 // marchid
+	MachineImplID              Value128 // mimpid
+	InstructionSet             RISCVExtensions
+	PrivilegeLevels            RISCVPrivilege
+	MachineExceptionDelegation Value128 // medeleg
+	MachineInterruptDelegation Value128 // mideleg
+	XLEN                       RISCVXLEN
+	MachineXLEN                RISCVXLEN
+	SupervisorXLEN             RISCVXLEN
+	UserXLEN                   RISCVXLEN
+}
+
+// riscvMinLength is the data length up to and including the privilege
+// levels; the delegation and XLEN fields that follow are optional
+const riscvMinLength = 0x49
+
+// RISCV decodes the block of a RISC-V processor
+func (b *ProcessorSpecificBlock) RISCV() (*RISCVBlock, error) {
+	switch b.ProcessorType {
+	case ProcessorTypeRISCV32, ProcessorTypeRISCV64, ProcessorTypeRISCV128:
+	default:
+		return nil, gosmbios.ErrInvalidStructure
+	}
+	d := b.Data
+	if len(d) < riscvMinLength {
+		return nil, gosmbios.ErrInvalidStructure
+	}
+
+	r := &RISCVBlock{
+		Revision:        binary.LittleEndian.Uint16(d[0x00:]),
+		Length:          d[0x02],
+		BootHart:        d[0x13] == 1,
+		InstructionSet:  RISCVExtensions(binary.LittleEndian.Uint32(d[0x44:])),
+		PrivilegeLevels: RISCVPrivilege(d[0x48]),
+	}
+	copy(r.HartID[:], d[0x03:])
+	copy(r.MachineVendorID[:], d[0x14:])
+	copy(r.MachineArchID[:], d[0x24:])
+	copy(r.MachineImplID[:], d[0x34:])
+
+	if len(d) >= 0x69 {
+		copy(r.MachineExceptionDelegation[:], d[0x49:])
+		copy(r.MachineInterruptDelegation[:], d[0x59:])
+	}
+	if len(d) >= 0x6E {
+		r.XLEN = RISCVXLEN(d[0x69])
+		r.MachineXLEN = RISCVXLEN(d[0x6A])
+		r.SupervisorXLEN = RISCVXLEN(d[0x6C])
+		r.UserXLEN = RISCVXLEN(d[0x6D])
+	}
+
+	// Fall back to the width implied by the processor type
+	if r.XLEN == RISCVXLENUnsupported {
+		r.XLEN = RISCVXLEN(b.ProcessorType - ProcessorTypeRISCV32 + 1)
+	}
+	return r, nil
+}
+
+// RevisionString returns the block revision as "major.minor"
+func (r *RISCVBlock) RevisionString() string {
+	return fmt.Sprintf("%d.%d", r.Revision>>8, r.Revision&0xFF)
+}
+
+// riscvOrder is the canonical order of single-letter extensions in ISA
+// strings; S and U name privilege modes and X non-standard extensions, so
+// they are left out
+const riscvOrder = "IEMAFDQCLBJKTPVH"
+
+// ISAString returns the ISA string, such as "rv64imafdc_zicsr". Only the
+// single-letter extensions are recorded; Zicsr is added when F is present
+// or any privilege level is reported, since both require it.
+func (r *RISCVBlock) ISAString() string {
+	var b strings.Builder
+	b.WriteString("rv")
+	if bits := r.XLEN.Bits(); bits != 0 {
+		fmt.Fprintf(&b, "%d", bits)
+	}
+	for i := 0; i < len(riscvOrder); i++ {
+		if r.InstructionSet.Has(riscvOrder[i]) {
+			b.WriteByte(riscvOrder[i] + ('a' - 'A'))
+		}
+	}
+	if r.InstructionSet.Has('F') || r.PrivilegeLevels != 0 {
+		b.WriteString("_zicsr")
+	}
+	return b.String()
+}
+
+// LoongArchBlock is the LoongArch processor-specific block. The LoongArch
+// fields are not decoded yet: only the revision and length that every block
+// starts with are split off, and the rest is left in Data.
+type LoongArchBlock struct {
+	Revision uint16 // Major in bits 15:8, minor in bits 7:0
+	Length   uint8
+	Data     []byte // The block after the length, undecoded
+}
+
+// LoongArch decodes the block of a LoongArch processor
+func (b *ProcessorSpecificBlock) LoongArch() (*LoongArchBlock, error) {
+	if b.ProcessorType != ProcessorTypeLoongArch32 && b.ProcessorType != ProcessorTypeLoongArch64 {
+		return nil, gosmbios.ErrInvalidStructure
+	}
+	if len(b.Data) < 3 {
+		return nil, gosmbios.ErrInvalidStructure
+	}
+	return &LoongArchBlock{
+		Revision: binary.LittleEndian.Uint16(b.Data[0x00:]),
+		Length:   b.Data[0x02],
+		Data:     b.Data[0x03:],
+	}, nil
+}
+
+// RevisionString returns the block revision as "major.minor"
+func (l *LoongArchBlock) RevisionString() string {
+	return fmt.Sprintf("%d.%d", l.Revision>>8, l.Revision&0xFF)
+}
+
+// Processor returns the Type 4 structure this information refers to
+func (p *ProcessorAdditionalInfo) Processor(sm *gosmbios.SMBIOS) (*type4.ProcessorInfo, error) {
+	return type4.GetByHandle(sm, p.ReferencedHandle)
+}
+
+// GetForProcessor retrieves the Processor Additional Information structures
+// that refer to the Type 4 structure with the given handle, one per hart or
+// core on systems that describe them
+func GetForProcessor(sm *gosmbios.SMBIOS, handle uint16) ([]*ProcessorAdditionalInfo, error) {
+	infos, err := GetAll(sm)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*ProcessorAdditionalInfo
+	for _, info := range infos {
+		if info.ReferencedHandle == handle {
+			matched = append(matched, info)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, gosmbios.ErrNotFound
+	}
+	return matched, nil
+}